@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// Data sources an idea can be read from.
+const (
+	// SourceHTML scrapes and parses the idea's pages.
+	SourceHTML = "html"
+	// SourceAPI reads the idea record from the Supabase REST API.
+	SourceAPI = "api"
+	// SourceAuto tries the REST API and falls back to scraping HTML.
+	SourceAuto = "auto"
+)
+
+// ParseSource validates a data source name.
+func ParseSource(s string) (string, error) {
+	switch s {
+	case SourceHTML, SourceAPI, SourceAuto:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown source %q (want %s, %s or %s)", s, SourceAPI, SourceHTML, SourceAuto)
+}
+
+// FetchIdea reads the record of slug from the Supabase REST API with the
+// client's session and normalizes it into IdeaData. Requests share the
+// client's rate limit and retry policy.
+func (c *Client) FetchIdea(ctx context.Context, slug string) (*model.IdeaData, error) {
+	if err := c.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.scheduler.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	var record map[string]interface{}
+	attempts, err := c.retrying(ctx, "idea record "+slug, func(token string) error {
+		var err error
+		record, err = c.rest.Idea(ctx, token, slug)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.debugf("Fetched idea record %s from the API (%d attempts)", slug, attempts)
+
+	idea, ok := extract.Record(c.site, slug, record)
+	if !ok {
+		return nil, fmt.Errorf("idea record %s has no title", slug)
+	}
+	return idea, nil
+}
@@ -0,0 +1,95 @@
+// Package api reads idea records straight from the Supabase REST (PostgREST)
+// endpoint that backs IdeaBrowser, instead of scraping rendered pages.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+)
+
+// DefaultTable is the table queried for idea records when REST.Table is empty.
+const DefaultTable = "ideas"
+
+// ErrNotFound is returned when no record matches the requested slug. Like
+// a response that does not decode, it is marked fetch.Permanent so the
+// request is not retried.
+var ErrNotFound = errors.New("idea not found")
+
+// REST queries the PostgREST API of a Supabase project under
+// ProjectURL + "/rest/v1/".
+type REST struct {
+	ProjectURL string
+	AnonKey    string
+	HTTPClient *http.Client
+
+	// Table holds one row per idea with a slug column; defaults to
+	// DefaultTable.
+	Table string
+}
+
+// Idea returns the record for slug as decoded JSON. Requests carry the anon
+// key and the user's access token, so row level security sees the same user
+// as the website does. Non-200 responses are returned as *fetch.StatusError.
+func (r *REST) Idea(ctx context.Context, accessToken, slug string) (map[string]interface{}, error) {
+	query := url.Values{
+		"select": {"*"},
+		"slug":   {"eq." + slug},
+		"limit":  {"1"},
+	}
+	var rows []map[string]interface{}
+	if err := r.get(ctx, accessToken, query, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fetch.Permanent(fmt.Errorf("%w: %s", ErrNotFound, slug))
+	}
+	return rows[0], nil
+}
+
+func (r *REST) get(ctx context.Context, accessToken string, query url.Values, v interface{}) error {
+	table := r.Table
+	if table == "" {
+		table = DefaultTable
+	}
+	endpoint := strings.TrimSuffix(r.ProjectURL, "/") + "/rest/v1/" + table + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("apikey", r.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &fetch.StatusError{
+			URL:        endpoint,
+			StatusCode: resp.StatusCode,
+			RetryAfter: fetch.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fetch.Permanent(fmt.Errorf("invalid response from %s: %v", table, err))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+)
+
+func TestIdea(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/v1/ideas" {
+			t.Errorf("path = %q, want /rest/v1/ideas", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("select") != "*" || q.Get("limit") != "1" {
+			t.Errorf("query = %q", r.URL.RawQuery)
+		}
+		if got := r.Header.Get("apikey"); got != "anon" {
+			t.Errorf("apikey = %q, want anon", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer access" {
+			t.Errorf("Authorization = %q, want Bearer access", got)
+		}
+
+		switch q.Get("slug") {
+		case "eq.nook":
+			w.Write([]byte(`[{"slug":"nook","title":"Nook"}]`))
+		case "eq.missing":
+			w.Write([]byte(`[]`))
+		default:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	r := &REST{ProjectURL: srv.URL + "/", AnonKey: "anon", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	record, err := r.Idea(ctx, "access", "nook")
+	if err != nil {
+		t.Fatalf("Idea(nook): %v", err)
+	}
+	if record["title"] != "Nook" {
+		t.Errorf("title = %v, want Nook", record["title"])
+	}
+
+	if _, err := r.Idea(ctx, "access", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Idea(missing) error = %v, want ErrNotFound", err)
+	}
+
+	_, err = r.Idea(ctx, "access", "down")
+	var statusErr *fetch.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable || statusErr.RetryAfter.Seconds() != 3 {
+		t.Errorf("Idea(down) error = %#v, want 503 StatusError with 3s Retry-After", err)
+	}
+}
@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/rubinkazan/ideabrowser-scraper/api"
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+)
+
+// supabaseStub stands in for the Supabase auth and REST endpoints. Access
+// tokens are numbered; the first one is rejected by the REST API so that
+// clients must refresh once before their query succeeds. It returns the
+// number of refresh grants and of queries answered with a valid token. The
+// slug "broken" gets a response that is not JSON.
+func supabaseStub(t *testing.T, record string) (srv *httptest.Server, refreshes, queries *int32) {
+	var issued, refreshCount, queryCount int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("grant_type") == "refresh_token" {
+			atomic.AddInt32(&refreshCount, 1)
+		}
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","refresh_token":"refresh-%d","expires_in":3600}`, n, n)
+	})
+	mux.HandleFunc("/rest/v1/ideas", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&queryCount, 1)
+		switch r.URL.Query().Get("slug") {
+		case "eq.nook":
+		case "eq.broken":
+			io.WriteString(w, `<html>maintenance</html>`)
+			return
+		default:
+			io.WriteString(w, `[]`)
+			return
+		}
+		io.WriteString(w, "["+record+"]")
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &refreshCount, &queryCount
+}
+
+// newTestClient returns an authenticated client that tries every request
+// once, or follows retry when it is given.
+func newTestClient(t *testing.T, projectURL string, retry ...fetch.RetryPolicy) *Client {
+	policy := fetch.RetryPolicy{MaxAttempts: 1}
+	if len(retry) > 0 {
+		policy = retry[0]
+	}
+	c, err := NewClient(Config{
+		AnonKey:    "anon",
+		ProjectURL: projectURL,
+		Email:      "user@example.com",
+		Password:   "secret",
+		BaseURL:    projectURL,
+		Logger:     log.New(io.Discard, "", 0),
+		RateLimit:  rate.Inf,
+		Retry:      policy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Authenticate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestFetchIdea(t *testing.T) {
+	srv, refreshes, _ := supabaseStub(t, `{
+		"slug": "nook",
+		"title": "Nook",
+		"description": "Quiet pods for remote workers.",
+		"published_at": "2025-01-17",
+		"tags": ["SaaS", {"name": "B2C"}],
+		"value_equation": {"score": 8, "dream_outcome": {"score": 9, "description": "Focus on demand."}},
+		"market_matrix": {"position": "Category King", "uniqueness": 8, "value": 9},
+		"acp_framework": {"audience_score": 9, "community_score": 6, "product_score": 8},
+		"why_now": {"market_timing": "Remote work is here to stay."}
+	}`)
+	c := newTestClient(t, srv.URL)
+
+	idea, err := c.FetchIdea(context.Background(), "nook")
+	if err != nil {
+		t.Fatalf("FetchIdea: %v", err)
+	}
+	if got := atomic.LoadInt32(refreshes); got != 1 {
+		t.Errorf("refreshes = %d, want 1 after the first token is rejected", got)
+	}
+
+	if idea.Title != "Nook" || idea.Description != "Quiet pods for remote workers." || idea.Date != "Jan 17, 2025" {
+		t.Errorf("idea info = %q, %q, %q", idea.Title, idea.Description, idea.Date)
+	}
+	if len(idea.Tags) != 2 || idea.Tags[0] != "SaaS" || idea.Tags[1] != "B2C" {
+		t.Errorf("tags = %q", idea.Tags)
+	}
+	fw := idea.FrameworkFit
+	if fw.ValueEquation.Score != 8 || fw.ValueEquation.Rating != "Excellent" {
+		t.Errorf("value equation = %+v", fw.ValueEquation)
+	}
+	if fw.MarketMatrix.Position != "Category King" || fw.MarketMatrix.Uniqueness != "8/10" || fw.MarketMatrix.Value != "9/10" {
+		t.Errorf("market matrix = %+v", fw.MarketMatrix)
+	}
+	if fw.ACPFramework.Overall != 7 {
+		t.Errorf("acp overall = %d, want 7", fw.ACPFramework.Overall)
+	}
+	if w := idea.WhyNow; w == nil || len(w.Drivers) != 1 || w.Drivers[0].Evidence != "Remote work is here to stay." {
+		t.Errorf("why now = %+v", idea.WhyNow)
+	}
+}
+
+// TestFetchIdeaNotFound checks that a missing record and a response that
+// does not decode fail at once under the default retry policy, so -source
+// auto falls back to scraping without backing off first.
+func TestFetchIdeaNotFound(t *testing.T) {
+	for _, slug := range []string{"missing", "broken"} {
+		srv, _, queries := supabaseStub(t, `{}`)
+		c := newTestClient(t, srv.URL, fetch.DefaultRetryPolicy)
+
+		start := time.Now()
+		_, err := c.FetchIdea(context.Background(), slug)
+		if err == nil {
+			t.Fatalf("FetchIdea(%s) succeeded, want error", slug)
+		}
+		if slug == "missing" && !errors.Is(err, api.ErrNotFound) {
+			t.Errorf("FetchIdea(missing) error = %v, want api.ErrNotFound", err)
+		}
+		if n := atomic.LoadInt32(queries); n != 1 {
+			t.Errorf("FetchIdea(%s) made %d queries, want 1", slug, n)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("FetchIdea(%s) took %s", slug, elapsed)
+		}
+	}
+}
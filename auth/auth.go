@@ -0,0 +1,155 @@
+// Package auth talks to the Supabase auth endpoints that back IdeaBrowser
+// and installs the resulting session as a cookie the website accepts.
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// TokenResponse is the session returned by the Supabase token endpoint.
+type TokenResponse struct {
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    int         `json:"expires_in"`
+	TokenType    string      `json:"token_type"`
+	User         interface{} `json:"user,omitempty"`
+}
+
+// Supabase performs token grants against a Supabase project.
+type Supabase struct {
+	ProjectURL string
+	AnonKey    string
+	HTTPClient *http.Client
+}
+
+// LoginWithEmail authenticates using email and password. It returns the
+// session and the Unix time at which the access token expires.
+func (s *Supabase) LoginWithEmail(ctx context.Context, email, password string) (*TokenResponse, int64, error) {
+	body, status, err := s.grant(ctx, "password", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if status != http.StatusOK {
+		return nil, 0, newGrantError("login failed", status, body)
+	}
+	return decodeToken(body)
+}
+
+// RefreshToken exchanges a refresh token for a new session.
+func (s *Supabase) RefreshToken(ctx context.Context, refreshToken string) (*TokenResponse, int64, error) {
+	body, status, err := s.grant(ctx, "refresh_token", map[string]string{
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if status != http.StatusOK {
+		return nil, 0, newGrantError("failed to refresh token", status, body)
+	}
+	return decodeToken(body)
+}
+
+func (s *Supabase) grant(ctx context.Context, grantType string, data map[string]string) ([]byte, int, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.ProjectURL+"/auth/v1/token?grant_type="+grantType, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("apikey", s.AnonKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// decodeToken decodes a session and when its access token expires: the
+// token's own exp claim, or expires_in from now when it has none.
+func decodeToken(body []byte) (*TokenResponse, int64, error) {
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, 0, err
+	}
+	if exp, ok := TokenExpiry(tokenResp.AccessToken); ok {
+		return &tokenResp, exp.Unix(), nil
+	}
+	expiresAt := time.Now().Unix() + int64(tokenResp.ExpiresIn)
+	return &tokenResp, expiresAt, nil
+}
+
+// GrantError is a token request the auth server refused.
+type GrantError struct {
+	Op     string
+	Status int
+	Body   string
+	// Code is the OAuth error, such as "invalid_grant", or the GoTrue
+	// error_code, such as "refresh_token_not_found".
+	Code string
+}
+
+func newGrantError(op string, status int, body []byte) *GrantError {
+	var reply struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"error_code"`
+	}
+	json.Unmarshal(body, &reply)
+	code := reply.Error
+	if reply.ErrorCode != "" {
+		code = reply.ErrorCode
+	}
+	return &GrantError{Op: op, Status: status, Body: string(body), Code: code}
+}
+
+func (e *GrantError) Error() string {
+	return fmt.Sprintf("%s: status %d, body: %s", e.Op, e.Status, e.Body)
+}
+
+// invalidGrantCodes are the error codes of a refresh token that can never
+// be used again, as opposed to a refresh that failed for now.
+var invalidGrantCodes = map[string]bool{
+	"invalid_grant":              true,
+	"refresh_token_not_found":    true,
+	"refresh_token_already_used": true,
+	"session_not_found":          true,
+	"session_expired":            true,
+}
+
+// InvalidGrant reports whether the refused grant was a refresh token that
+// was revoked, expired or already rotated away.
+func (e *GrantError) InvalidGrant() bool {
+	return invalidGrantCodes[e.Code]
+}
+
+var projectIDRe = regexp.MustCompile(`https://([^.]+)\.supabase\.co`)
+
+// ProjectID extracts the project ID from the Supabase project URL
+func ProjectID(projectURL string) string {
+	// Extract from URL like https://chqfunawciniepaqtdbd.supabase.co
+	matches := projectIDRe.FindStringSubmatch(projectURL)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxChunkSize is the longest cookie value, once URI-encoded, that
+// @supabase/ssr stores in a single cookie. Longer sessions are split into
+// chunks named <name>.0, <name>.1, ... in its place.
+const MaxChunkSize = 3180
+
+// base64Prefix marks a cookie value holding base64-encoded session JSON.
+const base64Prefix = "base64-"
+
+// CookieName returns the auth cookie name the website expects for the
+// project, in the format sb-[project-id]-auth-token.
+func CookieName(projectURL string) string {
+	return fmt.Sprintf("sb-%s-auth-token", ProjectID(projectURL))
+}
+
+// SetSessionCookie stores the session as the Supabase auth cookie for
+// siteURL in jar and returns the cookie name. The value is split into
+// chunks the way @supabase/ssr splits it when it exceeds MaxChunkSize, and
+// any cookie of an earlier session that the new value does not overwrite,
+// such as the unchunked cookie or a trailing chunk, is deleted.
+func SetSessionCookie(jar http.CookieJar, siteURL, projectURL string, tokenResp *TokenResponse) (string, error) {
+	parsedURL, err := url.Parse(siteURL)
+	if err != nil {
+		return "", err
+	}
+
+	// Encode the entire token response as base64url for the cookie value,
+	// as @supabase/ssr does
+	cookieName := CookieName(projectURL)
+	tokenJSON, err := json.Marshal(tokenResp)
+	if err != nil {
+		return "", err
+	}
+	chunks := splitChunks(base64Prefix + base64.RawURLEncoding.EncodeToString(tokenJSON))
+
+	names := make(map[string]bool)
+	var cookies []*http.Cookie
+	for i, chunk := range chunks {
+		name := cookieName
+		if len(chunks) > 1 {
+			name = cookieName + "." + strconv.Itoa(i)
+		}
+		names[name] = true
+		cookies = append(cookies, sessionCookie(parsedURL, name, chunk))
+	}
+	for _, old := range jar.Cookies(parsedURL) {
+		if _, ok := chunkIndex(old.Name, cookieName); ok && !names[old.Name] {
+			stale := sessionCookie(parsedURL, old.Name, "")
+			stale.MaxAge = -1
+			cookies = append(cookies, stale)
+		}
+	}
+
+	jar.SetCookies(parsedURL, cookies)
+	return cookieName, nil
+}
+
+// ReadSessionCookie returns the session stored in jar for siteURL under the
+// project's auth cookie, reassembling it from its chunks when it was split,
+// or nil when there is none.
+func ReadSessionCookie(jar http.CookieJar, siteURL, projectURL string) (*TokenResponse, error) {
+	parsedURL, err := url.Parse(siteURL)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := joinChunks(jar.Cookies(parsedURL), CookieName(projectURL))
+	if !ok {
+		return nil, nil
+	}
+
+	data := []byte(value)
+	if strings.HasPrefix(value, base64Prefix) {
+		// Older versions of this package wrote standard base64
+		encoded := strings.TrimRight(strings.TrimPrefix(value, base64Prefix), "=")
+		if data, err = base64.RawURLEncoding.DecodeString(encoded); err != nil {
+			if data, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+				return nil, fmt.Errorf("invalid session cookie: %v", err)
+			}
+		}
+	}
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(data, &tokenResp); err != nil {
+		return nil, fmt.Errorf("invalid session cookie: %v", err)
+	}
+	return &tokenResp, nil
+}
+
+func sessionCookie(u *url.URL, name, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   u.Hostname(),
+		Path:     "/",
+		HttpOnly: false,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// splitChunks splits value into the chunks @supabase/ssr would store it
+// in: each at most MaxChunkSize long once URI-encoded, or value alone when
+// it fits in one cookie.
+func splitChunks(value string) []string {
+	var chunks []string
+	start, size := 0, 0
+	for i := 0; i < len(value); i++ {
+		n := encodedLen(value[i])
+		if size+n > MaxChunkSize {
+			chunks = append(chunks, value[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	return append(chunks, value[start:])
+}
+
+// encodedLen returns the length of c once encoded by encodeURIComponent.
+func encodedLen(c byte) int {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return 1
+	case strings.IndexByte("-_.!~*'()", c) >= 0:
+		return 1
+	}
+	return 3
+}
+
+// joinChunks returns the value of the cookie called name, or the values of
+// its chunks name.0, name.1, ... joined in order. Chunks are read up to the
+// first missing index. ok is false when neither is among cookies.
+func joinChunks(cookies []*http.Cookie, name string) (value string, ok bool) {
+	chunks := make(map[int]string)
+	for _, c := range cookies {
+		i, isChunk := chunkIndex(c.Name, name)
+		switch {
+		case !isChunk:
+		case i < 0:
+			value, ok = c.Value, true
+		default:
+			chunks[i] = c.Value
+		}
+	}
+	if ok || len(chunks) == 0 {
+		return value, ok
+	}
+
+	indexes := make([]int, 0, len(chunks))
+	for i := range chunks {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	var b strings.Builder
+	for want, i := range indexes {
+		if i != want {
+			break
+		}
+		b.WriteString(chunks[i])
+	}
+	return b.String(), b.Len() > 0
+}
+
+// chunkIndex reports whether cookie is the cookie called name (index -1)
+// or one of its chunks name.<index>.
+func chunkIndex(cookie, name string) (index int, ok bool) {
+	if cookie == name {
+		return -1, true
+	}
+	suffix, found := strings.CutPrefix(cookie, name+".")
+	if !found {
+		return 0, false
+	}
+	i, err := strconv.Atoi(suffix)
+	if err != nil || i < 0 || strconv.Itoa(i) != suffix {
+		return 0, false
+	}
+	return i, true
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	testSite    = "https://ideabrowser.com"
+	testProject = "https://abcdefgh.supabase.co"
+)
+
+// jarNames returns the names of the cookies jar sends to testSite, sorted.
+func jarNames(t *testing.T, jar http.CookieJar) []string {
+	t.Helper()
+	u, _ := url.Parse(testSite)
+	var names []string
+	for _, c := range jar.Cookies(u) {
+		names = append(names, c.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// testSession returns a session whose user object makes its cookie value
+// roughly size bytes long.
+func testSession(access string, size int) *TokenResponse {
+	return &TokenResponse{
+		AccessToken:  access,
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+		TokenType:    "bearer",
+		User:         map[string]interface{}{"id": "user", "bio": strings.Repeat("x", size*3/4)},
+	}
+}
+
+func TestSessionCookieChunks(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := func(tokenResp *TokenResponse) {
+		t.Helper()
+		if _, err := SetSessionCookie(jar, testSite, testProject, tokenResp); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadSessionCookie(jar, testSite, testProject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || got.AccessToken != tokenResp.AccessToken || !reflect.DeepEqual(got.User, tokenResp.User) {
+			t.Errorf("ReadSessionCookie = %+v", got)
+		}
+	}
+	const name = "sb-abcdefgh-auth-token"
+
+	set(testSession("small", 100))
+	if got, want := jarNames(t, jar), []string{name}; !reflect.DeepEqual(got, want) {
+		t.Errorf("small session cookies = %q, want %q", got, want)
+	}
+
+	set(testSession("large", 3*MaxChunkSize))
+	if got, want := jarNames(t, jar), []string{name + ".0", name + ".1", name + ".2", name + ".3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("large session cookies = %q, want %q", got, want)
+	}
+	u, _ := url.Parse(testSite)
+	for _, c := range jar.Cookies(u) {
+		if n := len(url.QueryEscape(c.Value)); n > MaxChunkSize {
+			t.Errorf("%s is %d bytes encoded", c.Name, n)
+		}
+	}
+
+	// A refresh to a shorter session drops the chunks it no longer fills
+	set(testSession("medium", MaxChunkSize))
+	if got, want := jarNames(t, jar), []string{name + ".0", name + ".1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("medium session cookies = %q, want %q", got, want)
+	}
+	set(testSession("small", 100))
+	if got, want := jarNames(t, jar), []string{name}; !reflect.DeepEqual(got, want) {
+		t.Errorf("small session cookies after refresh = %q, want %q", got, want)
+	}
+}
+
+// TestSessionCookieURLSafe checks the cookie is written as base64url, with
+// no '+', '/' or '=' for the site to misread, and reads back unchanged.
+func TestSessionCookieURLSafe(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The question marks put a '/' in the standard base64 encoding
+	session := &TokenResponse{AccessToken: "token", RefreshToken: "r", User: map[string]interface{}{"bio": "??>>?>"}}
+	name, err := SetSessionCookie(jar, testSite, testProject, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := url.Parse(testSite)
+	cookies := jar.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Name != name {
+		t.Fatalf("cookies = %v", cookies)
+	}
+	value := cookies[0].Value
+	encoded, ok := strings.CutPrefix(value, "base64-")
+	if !ok {
+		t.Fatalf("cookie value %q lacks the base64- prefix", value)
+	}
+	if i := strings.IndexFunc(encoded, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-' || r == '_')
+	}); i >= 0 {
+		t.Errorf("cookie value has %q, which is not URL-safe: %s", encoded[i], value)
+	}
+
+	got, err := ReadSessionCookie(jar, testSite, testProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.AccessToken != "token" || !reflect.DeepEqual(got.User, session.User) {
+		t.Errorf("ReadSessionCookie = %+v", got)
+	}
+}
+
+// TestReadSessionCookieSSR reads a session chunked by @supabase/ssr, which
+// encodes it as base64url.
+func TestReadSessionCookieSSR(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := "base64-" + base64.RawURLEncoding.EncodeToString([]byte(`{"access_token":"ssr","refresh_token":"r","user":{"bio":"??>>"}}`))
+	u, _ := url.Parse(testSite)
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "sb-abcdefgh-auth-token.1", Value: value[20:]},
+		{Name: "sb-abcdefgh-auth-token.0", Value: value[:20]},
+		{Name: "sb-abcdefgh-auth-token.x", Value: "ignored"},
+	})
+	got, err := ReadSessionCookie(jar, testSite, testProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.AccessToken != "ssr" || got.RefreshToken != "r" {
+		t.Errorf("ReadSessionCookie = %+v", got)
+	}
+
+	empty, _ := cookiejar.New(nil)
+	if got, err := ReadSessionCookie(empty, testSite, testProject); got != nil || err != nil {
+		t.Errorf("ReadSessionCookie of an empty jar = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/session"
+)
+
+// DefaultRefreshSkew is how long before its expiry an access token is
+// replaced when TokenSource.Skew is zero.
+const DefaultRefreshSkew = 2 * time.Minute
+
+// TokenSource keeps the session of one account, handing out access tokens
+// that stay valid for at least Skew and refreshing them ahead of expiry.
+// Concurrent callers share one refresh: the check and the refresh happen
+// under one lock, so callers that find the token stale while another
+// refreshes it get the new session instead of refreshing again. A refresh
+// token the server no longer accepts falls back to email/password login.
+// TokenSource is safe for concurrent use.
+type TokenSource struct {
+	Supabase *Supabase
+	Email    string
+	Password string
+
+	// Sessions, when set, receives every new session, rotated refresh
+	// token included. Sessions are saved under the lock in the order they
+	// were issued, so an older session never overwrites a newer one.
+	Sessions session.Store
+
+	// Skew is how long before expiry a token is refreshed; defaults to
+	// DefaultRefreshSkew.
+	Skew time.Duration
+
+	// OnSession, when set, is called under the lock with every new session
+	// before it is used, e.g. to install the session cookie.
+	OnSession func(tokenResp *TokenResponse, expiresAt int64) error
+
+	// Logf, when set, receives progress output.
+	Logf func(format string, args ...interface{})
+
+	mu           sync.Mutex
+	token        *TokenResponse
+	expiresAt    int64
+	refreshToken string
+}
+
+func (ts *TokenSource) skew() time.Duration {
+	if ts.Skew > 0 {
+		return ts.Skew
+	}
+	return DefaultRefreshSkew
+}
+
+func (ts *TokenSource) logf(format string, args ...interface{}) {
+	if ts.Logf != nil {
+		ts.Logf(format, args...)
+	}
+}
+
+// Start establishes the first session. A saved session is reused while its
+// access token stays valid for Skew and refreshed once it does not; login
+// is the fallback when there is none or its refresh fails.
+func (ts *TokenSource) Start(ctx context.Context) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.Sessions != nil {
+		saved, err := ts.Sessions.Load()
+		switch {
+		case err != nil:
+			ts.logf("Warning: failed to load saved session: %v", err)
+		case saved.Valid(time.Now().Add(ts.skew())):
+			return ts.restoreLocked(saved)
+		case saved != nil && saved.RefreshToken != "":
+			_, _, err := ts.refreshLocked(ctx, saved.RefreshToken)
+			if err == nil || ctx.Err() != nil {
+				return err
+			}
+			ts.logf("Refresh token failed, trying email/password login: %v", err)
+		}
+	}
+	_, _, err := ts.loginLocked(ctx, ts.Email, ts.Password)
+	return err
+}
+
+// restoreLocked installs a saved session without a token request.
+func (ts *TokenSource) restoreLocked(saved *session.Session) error {
+	tokenResp := &TokenResponse{
+		AccessToken:  saved.AccessToken,
+		RefreshToken: saved.RefreshToken,
+		ExpiresIn:    int(saved.ExpiresAt - time.Now().Unix()),
+		TokenType:    "bearer",
+	}
+	if ts.OnSession != nil {
+		if err := ts.OnSession(tokenResp, saved.ExpiresAt); err != nil {
+			return err
+		}
+	}
+	ts.token = tokenResp
+	ts.expiresAt = saved.ExpiresAt
+	ts.refreshToken = saved.RefreshToken
+	return nil
+}
+
+// Token returns an access token valid for at least Skew, refreshing the
+// session first when it is missing or about to expire.
+func (ts *TokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != nil && time.Now().Add(ts.skew()).Unix() < ts.expiresAt {
+		return ts.token.AccessToken, nil
+	}
+	if err := ts.renewLocked(ctx); err != nil {
+		return "", err
+	}
+	return ts.token.AccessToken, nil
+}
+
+// Current returns the access token of the current session without
+// checking its expiry, or "" before the first session.
+func (ts *TokenSource) Current() string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token == nil {
+		return ""
+	}
+	return ts.token.AccessToken
+}
+
+// Refresh replaces the session after a request made with staleToken was
+// rejected. When another caller already replaced that token its session is
+// kept instead.
+func (ts *TokenSource) Refresh(ctx context.Context, staleToken string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != nil && ts.token.AccessToken != staleToken {
+		return nil
+	}
+	return ts.renewLocked(ctx)
+}
+
+// Relogin replaces the session with a fresh email/password login after the
+// site turned away a request made with staleToken although the token was
+// accepted, e.g. by rendering a sign-in wall. When another caller already
+// replaced that token its session is kept instead.
+func (ts *TokenSource) Relogin(ctx context.Context, staleToken string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != nil && ts.token.AccessToken != staleToken {
+		return nil
+	}
+	_, _, err := ts.loginLocked(ctx, ts.Email, ts.Password)
+	return err
+}
+
+// Login authenticates with email and password and makes the result the
+// current session.
+func (ts *TokenSource) Login(ctx context.Context, email, password string) (*TokenResponse, int64, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.loginLocked(ctx, email, password)
+}
+
+// RefreshWith exchanges refreshToken for a new session and makes it the
+// current one.
+func (ts *TokenSource) RefreshWith(ctx context.Context, refreshToken string) (*TokenResponse, int64, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.refreshLocked(ctx, refreshToken)
+}
+
+// renewLocked refreshes the session, logging in again when there is no
+// refresh token or the server refuses it as an invalid grant.
+func (ts *TokenSource) renewLocked(ctx context.Context) error {
+	if ts.refreshToken == "" {
+		_, _, err := ts.loginLocked(ctx, ts.Email, ts.Password)
+		return err
+	}
+	_, _, err := ts.refreshLocked(ctx, ts.refreshToken)
+	var grantErr *GrantError
+	if !errors.As(err, &grantErr) || !grantErr.InvalidGrant() {
+		return err
+	}
+	ts.logf("Refresh token rejected (%s), logging in again", grantErr.Code)
+	_, _, err = ts.loginLocked(ctx, ts.Email, ts.Password)
+	return err
+}
+
+func (ts *TokenSource) loginLocked(ctx context.Context, email, password string) (*TokenResponse, int64, error) {
+	if email == "" || password == "" {
+		return nil, 0, errors.New("no email and password to log in with")
+	}
+	ts.logf("Authenticating with email/password...")
+	tokenResp, expiresAt, err := ts.Supabase.LoginWithEmail(ctx, email, password)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tokenResp, expiresAt, ts.setLocked(tokenResp, expiresAt)
+}
+
+func (ts *TokenSource) refreshLocked(ctx context.Context, refreshToken string) (*TokenResponse, int64, error) {
+	tokenResp, expiresAt, err := ts.Supabase.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tokenResp, expiresAt, ts.setLocked(tokenResp, expiresAt)
+}
+
+// setLocked makes a new session current and saves it. The refresh token it
+// replaces may already be unusable, so a failed save is only logged: the
+// next run falls back to login.
+func (ts *TokenSource) setLocked(tokenResp *TokenResponse, expiresAt int64) error {
+	if ts.OnSession != nil {
+		if err := ts.OnSession(tokenResp, expiresAt); err != nil {
+			return err
+		}
+	}
+	ts.token = tokenResp
+	ts.expiresAt = expiresAt
+	if tokenResp.RefreshToken != "" {
+		ts.refreshToken = tokenResp.RefreshToken
+	}
+	if ts.Sessions == nil {
+		return nil
+	}
+	err := ts.Sessions.Save(&session.Session{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: ts.refreshToken,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		ts.logf("Warning: %v", err)
+	}
+	return nil
+}
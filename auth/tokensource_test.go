@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/session"
+)
+
+// testJWT returns an unsigned JWT whose exp claim is exp.
+func testJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256"}`)) + "." +
+		enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"user","exp":%d}`, exp.Unix()))) + ".sig"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1737093600, 0)
+	if got, ok := TokenExpiry(testJWT(exp)); !ok || !got.Equal(exp) {
+		t.Errorf("TokenExpiry = %v, %v", got, ok)
+	}
+	for _, token := range []string{"", "opaque", "a.b.c", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".c"} {
+		if _, ok := TokenExpiry(token); ok {
+			t.Errorf("TokenExpiry(%q) found an expiry", token)
+		}
+	}
+}
+
+// authServer is a Supabase token endpoint that issues tokens expiring after
+// lifetime and rejects refresh tokens in revoked as invalid grants.
+type authServer struct {
+	lifetime  time.Duration
+	revoked   map[string]bool
+	logins    atomic.Int32
+	refreshes atomic.Int32
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	switch r.URL.Query().Get("grant_type") {
+	case "password":
+		s.logins.Add(1)
+	case "refresh_token":
+		s.refreshes.Add(1)
+		for token := range s.revoked {
+			if string(body) == fmt.Sprintf(`{"refresh_token":%q}`, token) {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"error":"invalid_grant","error_description":"Invalid Refresh Token: Already Used"}`)
+				return
+			}
+		}
+		// Let concurrent callers pile up behind the first refresh
+		time.Sleep(20 * time.Millisecond)
+	}
+	n := s.logins.Load() + s.refreshes.Load()
+	fmt.Fprintf(w, `{"access_token":%q,"refresh_token":"refresh-%d","expires_in":3600}`, testJWT(time.Now().Add(s.lifetime)), n)
+}
+
+func newTokenSource(t *testing.T, srv *authServer) (*TokenSource, *session.File) {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	store := &session.File{Path: filepath.Join(t.TempDir(), "session.json")}
+	return &TokenSource{
+		Supabase: &Supabase{ProjectURL: ts.URL, AnonKey: "anon", HTTPClient: ts.Client()},
+		Email:    "user@example.com",
+		Password: "secret",
+		Sessions: store,
+		Skew:     time.Minute,
+	}, store
+}
+
+// TestTokenSourceRefreshesAhead checks that a token expiring within the skew
+// is refreshed once, however many workers ask for it at the same time.
+func TestTokenSourceRefreshesAhead(t *testing.T) {
+	srv := &authServer{lifetime: 30 * time.Second}
+	ts, _ := newTokenSource(t, srv)
+	ctx := context.Background()
+	if err := ts.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens from now on outlive the skew
+	srv.lifetime = time.Hour
+	var wg sync.WaitGroup
+	tokens := make([]string, 8)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := ts.Token(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+			tokens[i] = token
+		}(i)
+	}
+	wg.Wait()
+	if n := srv.refreshes.Load(); n != 1 {
+		t.Errorf("made %d refreshes, want 1", n)
+	}
+	for _, token := range tokens {
+		if token != tokens[0] {
+			t.Fatalf("workers got different tokens")
+		}
+	}
+	if _, err := ts.Token(ctx); err != nil || srv.refreshes.Load() != 1 {
+		t.Errorf("valid token was refreshed again (%v)", err)
+	}
+}
+
+// TestTokenSourceInvalidGrant checks that a revoked refresh token falls back
+// to login and that the new session is saved.
+func TestTokenSourceInvalidGrant(t *testing.T) {
+	srv := &authServer{lifetime: time.Hour, revoked: map[string]bool{"stale": true}}
+	ts, store := newTokenSource(t, srv)
+	ctx := context.Background()
+
+	expired := &session.Session{AccessToken: testJWT(time.Now().Add(-time.Minute)), RefreshToken: "stale", ExpiresAt: time.Now().Add(-time.Minute).Unix()}
+	if err := store.Save(expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if srv.refreshes.Load() != 1 || srv.logins.Load() != 1 {
+		t.Errorf("made %d refreshes and %d logins, want 1 of each", srv.refreshes.Load(), srv.logins.Load())
+	}
+
+	// A refresh token revoked mid-run falls back to login as well
+	saved, err := store.Load()
+	if err != nil || saved.RefreshToken == "stale" || saved.AccessToken != ts.Current() {
+		t.Fatalf("saved session = %+v, %v", saved, err)
+	}
+	srv.revoked[saved.RefreshToken] = true
+	if err := ts.Refresh(ctx, ts.Current()); err != nil {
+		t.Fatal(err)
+	}
+	if srv.logins.Load() != 2 {
+		t.Errorf("made %d logins after the revoked refresh, want 2", srv.logins.Load())
+	}
+	if again, _ := store.Load(); again.RefreshToken == saved.RefreshToken {
+		t.Error("rotated refresh token was not saved")
+	}
+}
@@ -0,0 +1,295 @@
+// Package scraper is a library for scraping business ideas from
+// IdeaBrowser.com. A Client authenticates against the site's Supabase
+// backend, fetches the pages of an idea and turns them into model.IdeaData.
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/rubinkazan/ideabrowser-scraper/api"
+	"github.com/rubinkazan/ideabrowser-scraper/auth"
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+	"github.com/rubinkazan/ideabrowser-scraper/session"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+// DefaultBaseURL is the IdeaBrowser site scraped when Config.BaseURL is empty.
+const DefaultBaseURL = "https://www.ideabrowser.com"
+
+// Version is the library and CLI version.
+const Version = "1.0.0"
+
+// Config configures a Client.
+type Config struct {
+	// Supabase project credentials (public and the same for all users)
+	AnonKey    string
+	ProjectURL string
+
+	// IdeaBrowser account credentials
+	Email    string
+	Password string
+
+	// BaseURL is the site to scrape; defaults to DefaultBaseURL.
+	BaseURL string
+
+	// HTTPClient is used for every request. When nil a client with a 30s
+	// timeout is created. A cookie jar is installed if the client has none.
+	HTTPClient *http.Client
+
+	// Logger receives progress output; defaults to log.Default().
+	Logger *log.Logger
+
+	// Verbose enables detailed progress output.
+	Verbose bool
+
+	// Sessions, when set, keeps the session between runs so Authenticate
+	// can reuse an unexpired access token or refresh the saved one.
+	Sessions session.Store
+
+	// RefreshSkew is how long before its expiry the access token is
+	// refreshed; defaults to auth.DefaultRefreshSkew.
+	RefreshSkew time.Duration
+
+	// HTMLDir, when set, receives a copy of every scraped page as page_N.html.
+	HTMLDir string
+
+	// DB, when set, receives every idea saved by ParseAndSaveData.
+	DB *storage.SQLite
+
+	// Archive, when set, keeps every page fetched by ScrapeIdea.
+	Archive *storage.Archive
+
+	// Concurrency is the number of pages fetched in parallel; defaults to
+	// DefaultConcurrency.
+	Concurrency int
+
+	// RateLimit caps page requests per second across all workers; defaults
+	// to DefaultRateLimit. Use rate.Inf for no limit.
+	RateLimit rate.Limit
+
+	// Retry controls retries of failed page requests; defaults to
+	// fetch.DefaultRetryPolicy when MaxAttempts is zero.
+	Retry fetch.RetryPolicy
+
+	// APITable is the Supabase table FetchIdea reads idea records from;
+	// defaults to api.DefaultTable.
+	APITable string
+
+	// MinCompleteness and Strict configure the checks of saved ideas; see
+	// Store.
+	MinCompleteness float64
+	Strict          bool
+
+	// LayoutTolerance is the share of a page's structure that may change
+	// before ParseAndSaveData warns of layout drift; defaults to
+	// extract.DefaultLayoutTolerance.
+	LayoutTolerance float64
+
+	// AcceptLayout records every page parsed by ParseAndSaveData as the
+	// known-good layout, accepting any drift.
+	AcceptLayout bool
+}
+
+// Defaults for Config.Concurrency and Config.RateLimit.
+const (
+	DefaultConcurrency = 4
+	DefaultRateLimit   = rate.Limit(1)
+)
+
+// Client scrapes IdeaBrowser with its own HTTP session and credentials.
+type Client struct {
+	baseURL    string
+	site       *url.URL
+	projectURL string
+
+	httpClient *http.Client
+	tokens     *auth.TokenSource
+	rest       *api.REST
+	fetcher    *fetch.Fetcher
+	scheduler  *fetch.Scheduler
+	retry      fetch.RetryPolicy
+
+	logger  *log.Logger
+	verbose bool
+	htmlDir string
+	store   *Store
+	archive *storage.Archive
+
+	layoutTolerance float64
+	acceptLayout    bool
+}
+
+// NewClient validates cfg and returns a Client ready to authenticate.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.AnonKey == "" {
+		return nil, errors.New("SUPABASE_ANON_KEY is required")
+	}
+	if cfg.ProjectURL == "" {
+		return nil, errors.New("SUPABASE_PROJECT_URL is required")
+	}
+	if cfg.Email == "" || cfg.Password == "" {
+		return nil, errors.New("IDEABROWSER_EMAIL and IDEABROWSER_PASSWORD are required")
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cookie jar: %v", err)
+		}
+		httpClient.Jar = jar
+	}
+
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	site, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %v", err)
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	limit := cfg.RateLimit
+	if limit == 0 {
+		limit = DefaultRateLimit
+	}
+	retry := cfg.Retry
+	if retry.MaxAttempts <= 0 {
+		retry = fetch.DefaultRetryPolicy
+	}
+	layoutTolerance := cfg.LayoutTolerance
+	if layoutTolerance <= 0 {
+		layoutTolerance = extract.DefaultLayoutTolerance
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		site:       site,
+		projectURL: cfg.ProjectURL,
+		httpClient: httpClient,
+		rest: &api.REST{
+			ProjectURL: cfg.ProjectURL,
+			AnonKey:    cfg.AnonKey,
+			HTTPClient: httpClient,
+			Table:      cfg.APITable,
+		},
+		fetcher:   &fetch.Fetcher{Client: httpClient},
+		scheduler: fetch.NewScheduler(concurrency, limit),
+		retry:     retry,
+		logger:    logger,
+		verbose:   cfg.Verbose,
+		htmlDir:   cfg.HTMLDir,
+		archive:   cfg.Archive,
+		store: &Store{
+			DB:              cfg.DB,
+			Logger:          logger,
+			MinCompleteness: cfg.MinCompleteness,
+			Strict:          cfg.Strict,
+		},
+
+		layoutTolerance: layoutTolerance,
+		acceptLayout:    cfg.AcceptLayout,
+	}
+	c.tokens = &auth.TokenSource{
+		Supabase: &auth.Supabase{
+			ProjectURL: cfg.ProjectURL,
+			AnonKey:    cfg.AnonKey,
+			HTTPClient: httpClient,
+		},
+		Email:     cfg.Email,
+		Password:  cfg.Password,
+		Sessions:  cfg.Sessions,
+		Skew:      cfg.RefreshSkew,
+		OnSession: c.installSession,
+		Logf:      logger.Printf,
+	}
+	return c, nil
+}
+
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.verbose {
+		c.logger.Printf(format, args...)
+	}
+}
+
+// LoginWithEmail authenticates using email and password and installs the
+// session cookie on the client.
+func (c *Client) LoginWithEmail(ctx context.Context, email, password string) (*auth.TokenResponse, int64, error) {
+	return c.tokens.Login(ctx, email, password)
+}
+
+// RefreshSupabaseToken exchanges refreshToken for a new session and updates
+// the session cookie.
+func (c *Client) RefreshSupabaseToken(ctx context.Context, refreshToken string) (*auth.TokenResponse, int64, error) {
+	return c.tokens.RefreshWith(ctx, refreshToken)
+}
+
+// installSession sets the session cookie for a new session; the token
+// source calls it before the session is used.
+func (c *Client) installSession(tokenResp *auth.TokenResponse, expiresAt int64) error {
+	cookieName, err := auth.SetSessionCookie(c.httpClient.Jar, c.baseURL, c.projectURL, tokenResp)
+	if err != nil {
+		return err
+	}
+	c.debugf("New session (token expires at %s)", time.Unix(expiresAt, 0).Format(time.RFC3339))
+	c.debugf("Set auth cookie: %s", cookieName)
+	return nil
+}
+
+// Authenticate establishes a session. A session saved in Config.Sessions is
+// reused while its access token stays valid for the refresh skew and
+// refreshed once it does not; email/password login is the fallback.
+func (c *Client) Authenticate(ctx context.Context) error {
+	return c.tokens.Start(ctx)
+}
+
+// ensureSession refreshes the session when its access token is missing or
+// expires within the refresh skew, so no request goes out with a token
+// that could expire in flight. Workers that find the token stale at once
+// share a single refresh.
+func (c *Client) ensureSession(ctx context.Context) error {
+	if _, err := c.tokens.Token(ctx); err != nil {
+		return fmt.Errorf("token refresh failed: %w", err)
+	}
+	return nil
+}
+
+// accessToken returns the access token of the current session.
+func (c *Client) accessToken() string {
+	return c.tokens.Current()
+}
+
+// forceRefresh refreshes the session after a request made with staleToken
+// was rejected. If another worker already replaced that token the new
+// session is reused instead of refreshing again.
+func (c *Client) forceRefresh(ctx context.Context, staleToken string) error {
+	return c.tokens.Refresh(ctx, staleToken)
+}
+
+// relogin logs in again after a page fetched with staleToken came back as a
+// sign-in wall. Workers that hit the wall with the same token share one
+// login.
+func (c *Client) relogin(ctx context.Context, staleToken string) error {
+	return c.tokens.Relogin(ctx, staleToken)
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/session"
+)
+
+// TestAuthenticateSavedSession checks that a saved session is reused
+// without a token request while its access token is valid, and refreshed
+// and saved again once it has expired.
+func TestAuthenticateSavedSession(t *testing.T) {
+	var grants atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grants.Add(1)
+		if r.URL.Query().Get("grant_type") != "refresh_token" {
+			t.Errorf("unexpected grant %s", r.URL.RawQuery)
+		}
+		io.WriteString(w, `{"access_token":"new-access","refresh_token":"rotated","expires_in":3600}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	store := &session.File{Path: filepath.Join(t.TempDir(), "session.json")}
+	newClient := func() *Client {
+		c, err := NewClient(Config{
+			AnonKey:    "anon",
+			ProjectURL: srv.URL,
+			Email:      "user@example.com",
+			Password:   "secret",
+			BaseURL:    srv.URL,
+			Logger:     log.New(io.Discard, "", 0),
+			Sessions:   store,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	ctx := context.Background()
+
+	valid := &session.Session{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	if err := store.Save(valid); err != nil {
+		t.Fatal(err)
+	}
+	c := newClient()
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := grants.Load(); n != 0 || c.accessToken() != "access" {
+		t.Errorf("made %d token requests, token %q", n, c.accessToken())
+	}
+
+	valid.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	if err := store.Save(valid); err != nil {
+		t.Fatal(err)
+	}
+	c = newClient()
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := grants.Load(); n != 1 || c.accessToken() != "new-access" {
+		t.Errorf("made %d token requests, token %q", n, c.accessToken())
+	}
+	saved, err := store.Load()
+	if err != nil || saved.AccessToken != "new-access" || saved.RefreshToken != "rotated" {
+		t.Errorf("saved session = %+v, %v", saved, err)
+	}
+}
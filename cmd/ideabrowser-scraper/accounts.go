@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	scraper "github.com/rubinkazan/ideabrowser-scraper"
+)
+
+// defaultAccount names the single account given by IDEABROWSER_EMAIL and
+// IDEABROWSER_PASSWORD when IDEABROWSER_ACCOUNTS is not set.
+const defaultAccount = "default"
+
+// accountNameRe matches the names allowed in IDEABROWSER_ACCOUNTS. A name
+// also names the account's session directory, so it cannot hold path
+// separators or dots.
+var accountNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// account is one set of IdeaBrowser credentials.
+type account struct {
+	name     string
+	email    string
+	password string
+}
+
+// accountClient is an authenticated client for one account. Each has its
+// own cookie jar and session store.
+type accountClient struct {
+	name string
+	*scraper.Client
+}
+
+// loadAccounts reads the accounts listed in IDEABROWSER_ACCOUNTS, e.g.
+// "pro,free", each from IDEABROWSER_<NAME>_EMAIL and
+// IDEABROWSER_<NAME>_PASSWORD. Without the list the single account in
+// IDEABROWSER_EMAIL and IDEABROWSER_PASSWORD is used.
+func loadAccounts() ([]account, error) {
+	list := os.Getenv("IDEABROWSER_ACCOUNTS")
+	if strings.TrimSpace(list) == "" {
+		a := account{
+			name:     defaultAccount,
+			email:    os.Getenv("IDEABROWSER_EMAIL"),
+			password: os.Getenv("IDEABROWSER_PASSWORD"),
+		}
+		if a.email == "" || a.password == "" {
+			return nil, fmt.Errorf("IDEABROWSER_EMAIL and IDEABROWSER_PASSWORD environment variables are required")
+		}
+		return []account{a}, nil
+	}
+
+	var accounts []account
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if !accountNameRe.MatchString(name) {
+			return nil, fmt.Errorf("invalid account name %q in IDEABROWSER_ACCOUNTS: use lower-case letters, digits, - and _", name)
+		}
+		if name == defaultAccount {
+			return nil, fmt.Errorf("account name %q in IDEABROWSER_ACCOUNTS is reserved", name)
+		}
+		prefix := "IDEABROWSER_" + envName(name) + "_"
+		a := account{
+			name:     name,
+			email:    os.Getenv(prefix + "EMAIL"),
+			password: os.Getenv(prefix + "PASSWORD"),
+		}
+		if a.email == "" || a.password == "" {
+			return nil, fmt.Errorf("account %s: %sEMAIL and %sPASSWORD environment variables are required", name, prefix, prefix)
+		}
+		accounts = append(accounts, a)
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("IDEABROWSER_ACCOUNTS lists no accounts")
+	}
+	return accounts, nil
+}
+
+// envName turns an account name into the part of its environment variable
+// names, e.g. "team-pro" into "TEAM_PRO".
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r + 'A' - 'a'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// selectAccounts returns the account called name, or every account when
+// name is empty.
+func selectAccounts(accounts []account, name string) ([]account, error) {
+	if name == "" {
+		return accounts, nil
+	}
+	var names []string
+	for _, a := range accounts {
+		if a.name == name {
+			return []account{a}, nil
+		}
+		names = append(names, a.name)
+	}
+	return nil, fmt.Errorf("unknown account %q (configured: %s)", name, strings.Join(names, ", "))
+}
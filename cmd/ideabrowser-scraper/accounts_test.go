@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadAccounts(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		env   map[string]string
+		want  []string // account names, nil for an error
+		email string   // of the first account
+	}{
+		{"single", map[string]string{"IDEABROWSER_EMAIL": "me@example.com", "IDEABROWSER_PASSWORD": "pw"}, []string{defaultAccount}, "me@example.com"},
+		{"single without password", map[string]string{"IDEABROWSER_EMAIL": "me@example.com"}, nil, ""},
+		{"list", map[string]string{
+			"IDEABROWSER_ACCOUNTS":           " pro, team-free ,pro",
+			"IDEABROWSER_PRO_EMAIL":          "pro@example.com",
+			"IDEABROWSER_PRO_PASSWORD":       "pw",
+			"IDEABROWSER_TEAM_FREE_EMAIL":    "free@example.com",
+			"IDEABROWSER_TEAM_FREE_PASSWORD": "pw",
+			"IDEABROWSER_EMAIL":              "ignored@example.com",
+			"IDEABROWSER_PASSWORD":           "pw",
+		}, []string{"pro", "team-free"}, "pro@example.com"},
+		{"missing credentials", map[string]string{"IDEABROWSER_ACCOUNTS": "pro"}, nil, ""},
+		{"only separators", map[string]string{"IDEABROWSER_ACCOUNTS": " , ,"}, nil, ""},
+		{"path traversal", map[string]string{"IDEABROWSER_ACCOUNTS": "../x", "IDEABROWSER____X_EMAIL": "x@example.com", "IDEABROWSER____X_PASSWORD": "pw"}, nil, ""},
+		{"slash", map[string]string{"IDEABROWSER_ACCOUNTS": "a/b", "IDEABROWSER_A_B_EMAIL": "x@example.com", "IDEABROWSER_A_B_PASSWORD": "pw"}, nil, ""},
+		{"upper case", map[string]string{"IDEABROWSER_ACCOUNTS": "Pro", "IDEABROWSER_PRO_EMAIL": "x@example.com", "IDEABROWSER_PRO_PASSWORD": "pw"}, nil, ""},
+		{"reserved", map[string]string{"IDEABROWSER_ACCOUNTS": "default", "IDEABROWSER_DEFAULT_EMAIL": "x@example.com", "IDEABROWSER_DEFAULT_PASSWORD": "pw"}, nil, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"IDEABROWSER_ACCOUNTS", "IDEABROWSER_EMAIL", "IDEABROWSER_PASSWORD"} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			accounts, err := loadAccounts()
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("loadAccounts = %+v, want an error", accounts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, a := range accounts {
+				names = append(names, a.name)
+			}
+			if !reflect.DeepEqual(names, tt.want) || accounts[0].email != tt.email {
+				t.Errorf("loadAccounts = %+v, want %q with %s first", accounts, tt.want, tt.email)
+			}
+		})
+	}
+}
+
+func TestSelectAccounts(t *testing.T) {
+	accounts := []account{{name: "pro"}, {name: "free"}}
+	for _, tt := range []struct {
+		name string
+		want []string // nil for an error
+	}{
+		{"", []string{"pro", "free"}},
+		{"free", []string{"free"}},
+		{"team", nil},
+	} {
+		got, err := selectAccounts(accounts, tt.name)
+		var names []string
+		for _, a := range got {
+			names = append(names, a.name)
+		}
+		switch {
+		case tt.want == nil && err == nil:
+			t.Errorf("selectAccounts(%q) = %q, want an error", tt.name, names)
+		case tt.want != nil && (err != nil || !reflect.DeepEqual(names, tt.want)):
+			t.Errorf("selectAccounts(%q) = %q, %v, want %q", tt.name, names, err, tt.want)
+		}
+	}
+}
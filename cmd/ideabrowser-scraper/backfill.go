@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	scraper "github.com/rubinkazan/ideabrowser-scraper"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+// backfillOptions holds the flags of the backfill subcommand.
+type backfillOptions struct {
+	slugsFile string
+	discover  bool
+	listing   string
+	from      string
+	to        string
+	force     bool
+}
+
+// flags returns the backfill subcommand's flag set, parsing into o.
+func (o *backfillOptions) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	registerCommonFlags(fs)
+	fs.StringVar(&o.slugsFile, "slugs", "", "File with one slug per line (\"-\" for stdin)")
+	fs.BoolVar(&o.discover, "discover", false, "Discover past ideas from the site's listing pages")
+	fs.StringVar(&o.listing, "listing", strings.Join(scraper.DefaultArchivePaths, ","), "Comma-separated listing pages searched by -discover")
+	fs.StringVar(&o.from, "from", "", "Only scrape ideas published on or after this date (YYYY-MM-DD)")
+	fs.StringVar(&o.to, "to", "", "Only scrape ideas published on or before this date (YYYY-MM-DD)")
+	fs.BoolVar(&o.force, "force", false, "Scrape ideas even if they were already saved")
+	return fs
+}
+
+// runBackfill scrapes past ideas given as arguments, read from a file or
+// stdin, or discovered from the site's listing pages. Ideas already present
+// in the output directory or database are skipped. Without -account the
+// ideas are spread across all configured accounts in turn.
+func runBackfill(args []string) {
+	o := &backfillOptions{}
+	fs := o.flags()
+	fs.Parse(args)
+
+	var fromDate, toDate time.Time
+	var err error
+	if o.from != "" {
+		if fromDate, err = time.Parse("2006-01-02", o.from); err != nil {
+			log.Fatalf("Invalid -from date: %v", err)
+		}
+	}
+	if o.to != "" {
+		if toDate, err = time.Parse("2006-01-02", o.to); err != nil {
+			log.Fatalf("Invalid -to date: %v", err)
+		}
+	}
+
+	slugs := fs.Args()
+	if o.slugsFile != "" {
+		listed, err := readSlugs(o.slugsFile)
+		if err != nil {
+			log.Fatalf("Failed to read slugs: %v", err)
+		}
+		slugs = append(slugs, listed...)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	clients, db := setup(ctx, true)
+	if db != nil {
+		defer db.Close()
+	}
+
+	if o.discover {
+		found, err := clients[0].DiscoverIdeaSlugs(ctx, strings.Split(o.listing, ","))
+		if err != nil {
+			log.Fatalf("Failed to discover ideas: %v", err)
+		}
+		log.Printf("Discovered %d ideas", len(found))
+		slugs = append(slugs, found...)
+	}
+	if len(slugs) == 0 {
+		log.Fatalf("No slugs to backfill: pass slugs as arguments, use -slugs or -discover")
+	}
+
+	failed, err := backfill(ctx, clients, db, slugs, fromDate, toDate, o.force, func(ctx context.Context, client accountClient, slug string) error {
+		return scrapeAndSave(ctx, client.Client, slug, false)
+	})
+	if err != nil {
+		log.Fatalf("Backfill interrupted: %v", err)
+	}
+	if failed > 0 {
+		log.Fatalf("Backfill finished with %d failed ideas", failed)
+	}
+	log.Println("✓ Backfill completed successfully!")
+}
+
+// backfill scrapes each distinct slug with scrape, handing the ideas to
+// clients in turn. Ideas already saved are skipped unless force is set, and
+// so are ideas published before fromDate or after toDate when those are not
+// zero. It returns the number of ideas that failed, and the context's error
+// when it was cancelled.
+func backfill(ctx context.Context, clients []accountClient, db *storage.SQLite, slugs []string, fromDate, toDate time.Time, force bool, scrape func(context.Context, accountClient, string) error) (int, error) {
+	failed, next := 0, 0
+	seen := make(map[string]bool)
+	for i, slug := range slugs {
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		if !force && alreadySaved(ctx, db, slug) {
+			log.Printf("[%d/%d] Skipping %s (already saved)", i+1, len(slugs), slug)
+			continue
+		}
+
+		client := clients[next%len(clients)]
+		next++
+
+		if !fromDate.IsZero() || !toDate.IsZero() {
+			published, err := client.IdeaDate(ctx, slug)
+			if err != nil {
+				log.Printf("[%d/%d] Skipping %s: %v", i+1, len(slugs), slug, err)
+				continue
+			}
+			if (!fromDate.IsZero() && published.Before(fromDate)) || (!toDate.IsZero() && published.After(toDate)) {
+				log.Printf("[%d/%d] Skipping %s (published %s)", i+1, len(slugs), slug, published.Format("2006-01-02"))
+				continue
+			}
+		}
+
+		log.Printf("[%d/%d] Backfilling %s as %s", i+1, len(slugs), slug, client.name)
+		if err := scrape(ctx, client, slug); err != nil {
+			if ctx.Err() != nil {
+				return failed, ctx.Err()
+			}
+			log.Printf("ERROR: %s: %v", slug, err)
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// readSlugs reads one slug per line from path, or stdin for "-". Blank lines
+// and lines starting with # are ignored.
+func readSlugs(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var slugs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		slugs = append(slugs, line)
+	}
+	return slugs, scanner.Err()
+}
+
+// alreadySaved reports whether slug has a JSON file in the output directory
+// or a row in the database.
+func alreadySaved(ctx context.Context, db *storage.SQLite, slug string) bool {
+	if storage.HasJSON(outputDir, slug) {
+		return true
+	}
+	if db == nil {
+		return false
+	}
+	ok, err := db.HasIdea(ctx, slug)
+	if err != nil {
+		log.Printf("Warning: failed to check database for %s: %v", slug, err)
+	}
+	return ok
+}
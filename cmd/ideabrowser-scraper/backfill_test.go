@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	scraper "github.com/rubinkazan/ideabrowser-scraper"
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+)
+
+func TestReadSlugs(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "slugs.txt")
+	content := "# January\nfirst-idea\n\n  second-idea  \n#skipped\nthird-idea"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readSlugs(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first-idea", "second-idea", "third-idea"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readSlugs = %q, want %q", got, want)
+	}
+	if _, err := readSlugs(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("readSlugs of a missing file succeeded")
+	}
+}
+
+// testClients returns authenticated clients named names for a test site
+// publishing each idea of published on its date. Other ideas are not found.
+func testClients(t *testing.T, published map[string]string, names ...string) []accountClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"access_token":"token","refresh_token":"refresh","expires_in":3600}`)
+	})
+	mux.HandleFunc("/idea/", func(w http.ResponseWriter, r *http.Request) {
+		slug := path.Base(r.URL.Path)
+		date, ok := published[slug]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<html><h1>%s</h1><time datetime="%s"></time></html>`, slug, date)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var clients []accountClient
+	for _, name := range names {
+		c, err := scraper.NewClient(scraper.Config{
+			AnonKey:    "anon",
+			ProjectURL: srv.URL,
+			Email:      name + "@example.com",
+			Password:   "secret",
+			BaseURL:    srv.URL,
+			Logger:     log.New(io.Discard, "", 0),
+			RateLimit:  rate.Inf,
+			Retry:      fetch.RetryPolicy{MaxAttempts: 1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Authenticate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		clients = append(clients, accountClient{name: name, Client: c})
+	}
+	return clients
+}
+
+func TestBackfill(t *testing.T) {
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	dir := t.TempDir()
+	defer func(dir string) { outputDir = dir }(outputDir)
+	outputDir = dir
+	if err := os.WriteFile(filepath.Join(dir, "idea_saved_2025-01-12.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	clients := testClients(t, map[string]string{
+		"early":  "2025-01-02",
+		"first":  "2025-01-10",
+		"second": "2025-01-20",
+		"late":   "2025-02-01",
+		"broken": "2025-01-15",
+		"saved":  "2025-01-12",
+	}, "pro", "free")
+	var scraped []string
+	scrape := func(ctx context.Context, client accountClient, slug string) error {
+		scraped = append(scraped, client.name+":"+slug)
+		if slug == "broken" {
+			return errors.New("no pages")
+		}
+		return nil
+	}
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name     string
+		slugs    []string
+		from, to time.Time
+		force    bool
+		want     []string
+		failed   int
+	}{
+		{
+			name: "dates",
+			// Ideas outside the dates and missing ones still take a turn,
+			// as checking the date fetches a page
+			slugs:  []string{"first", "saved", "early", "second", "first", "late", "broken", "missing"},
+			from:   day("2025-01-05"),
+			to:     day("2025-01-31"),
+			want:   []string{"pro:first", "pro:second", "pro:broken"},
+			failed: 1,
+		},
+		{
+			name:  "round robin",
+			slugs: []string{"first", "second", "late", "saved"},
+			want:  []string{"pro:first", "free:second", "pro:late"},
+		},
+		{
+			name:  "force",
+			slugs: []string{"saved", "first"},
+			force: true,
+			want:  []string{"pro:saved", "free:first"},
+		},
+	} {
+		scraped = nil
+		failed, err := backfill(ctx, clients, nil, tt.slugs, tt.from, tt.to, tt.force, scrape)
+		if err != nil || failed != tt.failed {
+			t.Errorf("%s: backfill = %d, %v, want %d failed", tt.name, failed, err, tt.failed)
+		}
+		if !reflect.DeepEqual(scraped, tt.want) {
+			t.Errorf("%s: scraped %q, want %q", tt.name, scraped, tt.want)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	scraped = nil
+	failed, err := backfill(cancelled, clients, nil, []string{"first", "second"}, time.Time{}, time.Time{}, false, func(ctx context.Context, client accountClient, slug string) error {
+		scraped = append(scraped, slug)
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) || failed != 0 || len(scraped) != 1 {
+		t.Errorf("cancelled backfill = %d, %v after %q", failed, err, scraped)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+var filenameDateRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// ingestOptions holds the flags of the ingest subcommand.
+type ingestOptions struct {
+	db  string
+	dir string
+}
+
+// flags returns the ingest subcommand's flag set, parsing into o.
+func (o *ingestOptions) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("ingest", flag.ExitOnError)
+	fs.StringVar(&o.db, "db", "data/ideas.db", "SQLite database to import into (created if missing)")
+	fs.StringVar(&o.dir, "dir", "data/json", "Directory of JSON files to import when no files are given")
+	return fs
+}
+
+// runIngest imports previously scraped JSON files into the SQLite database.
+// With no file arguments every *.json in -dir is imported.
+func runIngest(args []string) {
+	o := &ingestOptions{}
+	fs := o.flags()
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		var err error
+		files, err = filepath.Glob(filepath.Join(o.dir, "*.json"))
+		if err != nil {
+			log.Fatalf("Failed to list JSON files: %v", err)
+		}
+		if len(files) == 0 {
+			log.Printf("No JSON files found in %s", o.dir)
+			return
+		}
+	}
+
+	ctx := context.Background()
+	if err := os.MkdirAll(filepath.Dir(o.db), 0755); err != nil {
+		log.Fatalf("Failed to create database directory: %v", err)
+	}
+	store, err := storage.OpenSQLite(ctx, o.db)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer store.Close()
+
+	imported := 0
+	for _, file := range files {
+		if err := ingestFile(ctx, store, file); err != nil {
+			log.Printf("ERROR: %s: %v", filepath.Base(file), err)
+			continue
+		}
+		imported++
+		log.Printf("Imported: %s", filepath.Base(file))
+	}
+
+	total, err := store.CountIdeas(ctx)
+	if err != nil {
+		log.Fatalf("Failed to count ideas: %v", err)
+	}
+	log.Printf("Processed %d/%d files successfully", imported, len(files))
+	log.Printf("Total ideas in database: %d", total)
+	if imported < len(files) {
+		os.Exit(1)
+	}
+}
+
+func ingestFile(ctx context.Context, store *storage.SQLite, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	// Files from older versions are upgraded to the current schema
+	idea, _, err := model.Upgrade(data)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	if idea.Slug == "" {
+		return fmt.Errorf("no slug found")
+	}
+
+	// Date the idea from the filename (idea_slug_2025-01-17.json) when the
+	// JSON has no usable date of its own
+	scrapedAt := time.Now()
+	if m := filenameDateRe.FindString(filepath.Base(file)); m != "" {
+		if t, err := time.Parse("2006-01-02", m); err == nil {
+			scrapedAt = t
+		}
+	}
+	return store.SaveIdea(ctx, idea, scrapedAt)
+}
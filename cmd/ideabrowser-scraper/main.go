@@ -0,0 +1,385 @@
+// Command ideabrowser-scraper scrapes today's idea from IdeaBrowser.com and
+// saves it as JSON.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	scraper "github.com/rubinkazan/ideabrowser-scraper"
+	"github.com/rubinkazan/ideabrowser-scraper/auth"
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+	"github.com/rubinkazan/ideabrowser-scraper/session"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+	"github.com/rubinkazan/ideabrowser-scraper/validate"
+)
+
+var (
+	// Command-line flags
+	outputDir   string
+	dbPath      string
+	archiveDir  string
+	sessionKind string
+	sessionDir  string
+	refreshSkew time.Duration
+	slugFlag    string
+	accountFlag string
+	concurrency int
+	rateFlag    string
+	source      string
+	maxAttempts int
+	strict      bool
+	minComplete float64
+	layoutTol   float64
+	acceptDrift bool
+	saveHTML    bool
+	verbose     bool
+	showHelp    bool
+	showVersion bool
+)
+
+func init() {
+	registerCommonFlags(flag.CommandLine)
+	flag.StringVar(&slugFlag, "slug", "", "Scrape this idea instead of today's idea")
+	flag.BoolVar(&showHelp, "help", false, "Show help message")
+	flag.BoolVar(&showVersion, "version", false, "Show version information")
+}
+
+// registerCommonFlags registers the flags shared by the default command and
+// the subcommands that scrape.
+func registerCommonFlags(fs *flag.FlagSet) {
+	fs.StringVar(&outputDir, "output", ".", "Output directory for scraped data")
+	fs.StringVar(&dbPath, "db", "", "SQLite database to store scraped ideas in (created if missing)")
+	fs.StringVar(&accountFlag, "account", "", "Account from IDEABROWSER_ACCOUNTS to log in as (default the first; backfill spreads ideas across all)")
+	fs.StringVar(&archiveDir, "archive", "", "Directory of the permanent raw page archive (default <output>/archive, \"none\" to disable)")
+	fs.StringVar(&sessionKind, "session-store", session.KindAuto, "Where the login session is kept between runs: age (encrypted with $"+session.PassphraseEnv+"), keyring (Secret Service), file (0600 JSON) or auto")
+	fs.DurationVar(&refreshSkew, "refresh-skew", auth.DefaultRefreshSkew, "Refresh the access token this long before it expires")
+	fs.StringVar(&sessionDir, "session-dir", "", "Directory of the age and file session stores (default the user config directory)")
+	fs.BoolVar(&saveHTML, "save-html", false, "Save raw HTML files for debugging")
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose logging")
+	fs.IntVar(&concurrency, "concurrency", scraper.DefaultConcurrency, "Number of pages fetched in parallel")
+	fs.IntVar(&maxAttempts, "retries", fetch.DefaultRetryPolicy.MaxAttempts, "Maximum attempts per page before giving up")
+	fs.StringVar(&rateFlag, "rate", "1/s", "Maximum request rate shared by all workers (e.g. 2/s, 30/m, or inf for no limit)")
+	fs.BoolVar(&strict, "strict", false, "Exit with an error when a saved idea does not match the schema or is less complete than -min-completeness")
+	fs.Float64Var(&minComplete, "min-completeness", validate.DefaultMinCompleteness, "Completeness (0-1) below which an idea's report flags it")
+	fs.Float64Var(&layoutTol, "layout-tolerance", extract.DefaultLayoutTolerance, "Share (0-1) of a page's structure that may change before a layout drift warning")
+	fs.BoolVar(&acceptDrift, "accept-layout", false, "Record this run's pages as the known-good layout, accepting any drift")
+	fs.StringVar(&source, "source", scraper.SourceHTML, "Where idea data is read from: api (Supabase REST), html (scraped pages) or auto (api, falling back to html)")
+}
+
+// loadConfig builds the client configuration shared by all accounts and
+// reads the accounts from the environment and the optional .env file.
+func loadConfig() (scraper.Config, []account, error) {
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		if !os.IsNotExist(err) {
+			return scraper.Config{}, nil, fmt.Errorf("error loading .env file: %v", err)
+		}
+	}
+
+	cfg := scraper.Config{
+		AnonKey:    os.Getenv("SUPABASE_ANON_KEY"),
+		ProjectURL: os.Getenv("SUPABASE_PROJECT_URL"),
+	}
+
+	// Validate required configuration
+	if cfg.AnonKey == "" {
+		return cfg, nil, fmt.Errorf("SUPABASE_ANON_KEY environment variable is required")
+	}
+	if cfg.ProjectURL == "" {
+		return cfg, nil, fmt.Errorf("SUPABASE_PROJECT_URL environment variable is required")
+	}
+	accounts, err := loadAccounts()
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	return cfg, accounts, nil
+}
+
+func printHelp() {
+	fmt.Printf("IdeaBrowser Scraper v%s\n\n", scraper.Version)
+	fmt.Println("A tool for scraping business ideas from IdeaBrowser.com")
+	fmt.Println("\nUsage:")
+	fmt.Println("  ideabrowser-scraper [options]")
+	fmt.Println("  ideabrowser-scraper backfill [options] [-slugs file] [-discover]")
+	fmt.Println("  ideabrowser-scraper ingest -db path [file.json ...]")
+	fmt.Println("  ideabrowser-scraper upgrade [-dir path] [-dry-run] [file.json ...]")
+	fmt.Println("  ideabrowser-scraper reparse [-dir path] [-archive path] [-db path] [-slug slug] [-from date] [-to date] [-dry-run]")
+	fmt.Println("\nOptions:")
+	flag.PrintDefaults()
+	fmt.Println("\nExamples:")
+	fmt.Println("  # Scrape today's idea")
+	fmt.Println("  ideabrowser-scraper")
+	fmt.Println("\n  # Scrape with verbose output")
+	fmt.Println("  ideabrowser-scraper -verbose")
+	fmt.Println("\n  # Save output to specific directory")
+	fmt.Println("  ideabrowser-scraper -output ./ideas")
+	fmt.Println("\n  # Scrape a specific idea")
+	fmt.Println("  ideabrowser-scraper -slug some-idea-slug")
+	fmt.Println("\n  # Backfill past ideas listed in a file, skipping ones already stored")
+	fmt.Println("  ideabrowser-scraper backfill -slugs slugs.txt -db ./data/ideas.db")
+	fmt.Println("\n  # Scrape as the account named pro in IDEABROWSER_ACCOUNTS")
+	fmt.Println("  ideabrowser-scraper -account pro")
+	fmt.Println("\n  # Fetch with 6 workers at up to 2 requests per second")
+	fmt.Println("  ideabrowser-scraper -concurrency 6 -rate 2/s")
+	fmt.Println("\n  # Read the idea from the Supabase API, scraping pages if that fails")
+	fmt.Println("  ideabrowser-scraper -source auto")
+	fmt.Println("\n  # Fail the run when fewer than 80% of the fields were extracted")
+	fmt.Println("  ideabrowser-scraper -strict -min-completeness 0.8")
+	fmt.Println("\n  # Show what the current extractors would change in last week's ideas")
+	fmt.Println("  ideabrowser-scraper reparse -dir ./data/json -archive ./data/archive -from 2025-01-10 -to 2025-01-17 -dry-run")
+	fmt.Println("\n  # Scrape and store in SQLite in one run")
+	fmt.Println("  ideabrowser-scraper -output ./data/json -db ./data/ideas.db")
+	fmt.Println("\nNote: Ensure you have set IDEABROWSER_EMAIL and IDEABROWSER_PASSWORD, or IDEABROWSER_ACCOUNTS, in your .env file")
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "ingest":
+			runIngest(os.Args[2:])
+			return
+		case "backfill":
+			runBackfill(os.Args[2:])
+			return
+		case "upgrade":
+			runUpgrade(os.Args[2:])
+			return
+		case "reparse":
+			runReparse(os.Args[2:])
+			return
+		}
+	}
+
+	flag.Parse()
+
+	if showHelp {
+		printHelp()
+		return
+	}
+
+	if showVersion {
+		fmt.Printf("IdeaBrowser Scraper v%s\n", scraper.Version)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	clients, db := setup(ctx, false)
+	if db != nil {
+		defer db.Close()
+	}
+	client := clients[0].Client
+
+	today := slugFlag == ""
+	slug := slugFlag
+	if today {
+		// Get today's idea slug from public page
+		log.Println("Fetching today's idea...")
+		var err error
+		slug, err = client.GetIdeaSlug(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get today's idea: %v\n", err)
+		}
+		log.Printf("Found today's idea: %s\n", slug)
+	}
+
+	if err := scrapeAndSave(ctx, client, slug, today); err != nil {
+		log.Fatalf("%v\n", err)
+	}
+
+	log.Println("✓ Scraping completed successfully!")
+}
+
+// setup loads the configuration, creates the output directory, opens the
+// database when -db is set and returns an authenticated client for the
+// account selected with -account, or the first account. With all and no
+// -account it returns a client for every account.
+func setup(ctx context.Context, all bool) ([]accountClient, *storage.SQLite) {
+	// Load configuration
+	cfg, accounts, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Configuration error: %v\n\nPlease ensure you have set up your .env file correctly.\nSee README.md for instructions.\n", err)
+	}
+
+	// Create output directory if it doesn't exist
+	if outputDir != "." {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			log.Fatalf("Failed to create output directory: %v", err)
+		}
+	}
+
+	cfg.Verbose = verbose
+	cfg.Concurrency = concurrency
+	cfg.Retry = fetch.DefaultRetryPolicy
+	cfg.Retry.MaxAttempts = maxAttempts
+	if cfg.RateLimit, err = fetch.ParseRate(rateFlag); err != nil {
+		log.Fatalf("Invalid -rate: %v", err)
+	}
+	if source, err = scraper.ParseSource(source); err != nil {
+		log.Fatalf("Invalid -source: %v", err)
+	}
+	if minComplete <= 0 || minComplete > 1 {
+		log.Fatalf("Invalid -min-completeness: %v (want a fraction above 0 and at most 1)", minComplete)
+	}
+	if layoutTol <= 0 || layoutTol > 1 {
+		log.Fatalf("Invalid -layout-tolerance: %v (want a fraction above 0 and at most 1)", layoutTol)
+	}
+	cfg.RefreshSkew = refreshSkew
+	cfg.Strict = strict
+	cfg.MinCompleteness = minComplete
+	cfg.LayoutTolerance = layoutTol
+	cfg.AcceptLayout = acceptDrift
+	if saveHTML {
+		cfg.HTMLDir = outputDir
+	}
+
+	var db *storage.SQLite
+	if dbPath != "" {
+		if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+			log.Fatalf("Failed to create database directory: %v", err)
+		}
+		db, err = storage.OpenSQLite(ctx, dbPath)
+		if err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		cfg.DB = db
+	}
+
+	switch archiveDir {
+	case "none":
+	case "":
+		archiveDir = filepath.Join(outputDir, "archive")
+		fallthrough
+	default:
+		if cfg.Archive, err = storage.OpenArchive(archiveDir); err != nil {
+			log.Fatalf("Failed to open archive: %v", err)
+		}
+	}
+
+	selected, err := selectAccounts(accounts, accountFlag)
+	if err != nil {
+		log.Fatalf("Invalid -account: %v", err)
+	}
+	if !all {
+		selected = selected[:1]
+	}
+
+	// Each account gets its own client, and so its own cookie jar, and its
+	// own session store
+	var clients []accountClient
+	for _, a := range selected {
+		acfg := cfg
+		acfg.Email = a.email
+		acfg.Password = a.password
+		if len(selected) > 1 {
+			acfg.Logger = log.New(log.Writer(), "["+a.name+"] ", log.Flags()|log.Lmsgprefix)
+		}
+		if acfg.Sessions, err = openSessions(a); err != nil {
+			log.Fatalf("Failed to open session store of account %s: %v", a.name, err)
+		}
+
+		client, err := scraper.NewClient(acfg)
+		if err != nil {
+			log.Fatalf("Failed to create client: %v", err)
+		}
+
+		// Setup authentication
+		if err := client.Authenticate(ctx); err != nil {
+			log.Fatalf("Authentication of account %s failed: %v\n", a.name, err)
+		}
+		clients = append(clients, accountClient{name: a.name, Client: client})
+	}
+	return clients, db
+}
+
+// openSessions opens the session store selected with -session-store for
+// account a. Named accounts keep their session files in a subdirectory of
+// -session-dir. A refresh_token.txt left in the output directory by older
+// versions is moved into the default account's store and deleted.
+func openSessions(a account) (session.Store, error) {
+	dir := sessionDir
+	if dir == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(configDir, "ideabrowser-scraper")
+	}
+	if a.name != defaultAccount {
+		dir = filepath.Join(dir, a.name)
+	}
+	store, err := session.Open(sessionKind, session.Options{
+		Dir:        dir,
+		Passphrase: os.Getenv(session.PassphraseEnv),
+		Service:    "ideabrowser-scraper",
+		User:       a.email,
+	})
+	if err != nil || a.name != defaultAccount {
+		return store, err
+	}
+
+	legacy := filepath.Join(outputDir, "refresh_token.txt")
+	data, err := os.ReadFile(legacy)
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return store, nil
+	}
+	saved, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		if err := store.Save(&session.Session{RefreshToken: string(bytes.TrimSpace(data))}); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.Remove(legacy); err != nil {
+		return nil, err
+	}
+	log.Printf("Moved the refresh token out of %s into the session store", legacy)
+	return store, nil
+}
+
+// scrapeAndSave reads slug from the source selected with -source and saves
+// the idea. With -source auto a failed API request falls back to scraping.
+func scrapeAndSave(ctx context.Context, client *scraper.Client, slug string, today bool) error {
+	if source == scraper.SourceAPI || source == scraper.SourceAuto {
+		idea, err := client.FetchIdea(ctx, slug)
+		if err == nil {
+			log.Printf("Read %s from the API", slug)
+			if err := client.SaveIdea(ctx, idea, outputDir); err != nil {
+				return fmt.Errorf("failed to save data: %v", err)
+			}
+			return nil
+		}
+		if source == scraper.SourceAPI || ctx.Err() != nil {
+			return fmt.Errorf("API request failed: %v", err)
+		}
+		log.Printf("API request failed, scraping pages instead: %v", err)
+	}
+
+	result, err := client.ScrapeIdea(ctx, slug, today)
+	if err != nil {
+		return fmt.Errorf("scraping failed: %v", err)
+	}
+	log.Printf("Scraped %s", result.Summary())
+
+	// Parse and save data to JSON
+	log.Println("Parsing scraped data...")
+	if err := client.ParseAndSaveData(ctx, slug, result.Pages, outputDir); err != nil {
+		return fmt.Errorf("failed to parse and save data: %v", err)
+	}
+	return nil
+}
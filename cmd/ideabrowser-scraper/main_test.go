@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+// TestFlagSets builds the flag set of every subcommand, which panics when
+// one defines a flag twice, and parses an empty command line with it.
+func TestFlagSets(t *testing.T) {
+	for name, flags := range map[string]func() *flag.FlagSet{
+		"backfill": new(backfillOptions).flags,
+		"reparse":  new(reparseOptions).flags,
+		"upgrade":  new(upgradeOptions).flags,
+		"ingest":   new(ingestOptions).flags,
+	} {
+		t.Run(name, func(t *testing.T) {
+			fs := flags()
+			if fs.Name() != name {
+				t.Errorf("flag set is named %q", fs.Name())
+			}
+			if err := fs.Parse(nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	// backfill takes the common -archive directory and its own -listing pages
+	fs := new(backfillOptions).flags()
+	if err := fs.Parse([]string{"-archive", "none", "-listing", "/ideas", "-discover"}); err != nil {
+		t.Fatal(err)
+	}
+	if archiveDir != "none" || fs.Lookup("listing").Value.String() != "/ideas" {
+		t.Errorf("-archive = %q, -listing = %q", archiveDir, fs.Lookup("listing").Value)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	scraper "github.com/rubinkazan/ideabrowser-scraper"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+	"github.com/rubinkazan/ideabrowser-scraper/validate"
+)
+
+// reparseOptions holds the flags of the reparse subcommand.
+type reparseOptions struct {
+	dir         string
+	archive     string
+	db          string
+	slug        string
+	from        string
+	to          string
+	dryRun      bool
+	minComplete float64
+}
+
+// flags returns the reparse subcommand's flag set, parsing into o.
+func (o *reparseOptions) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("reparse", flag.ExitOnError)
+	fs.StringVar(&o.dir, "dir", "data/json", "Directory of the saved JSON files")
+	fs.StringVar(&o.archive, "archive", "", "Directory of the raw page archive, as given to the scraper (default <dir>/archive, matching the scraper's <output>/archive)")
+	fs.StringVar(&o.db, "db", "", "SQLite database to update (JSON files only when empty)")
+	fs.StringVar(&o.slug, "slug", "", "Reparse only this idea")
+	fs.StringVar(&o.from, "from", "", "Reparse only pages fetched on or after this date (YYYY-MM-DD)")
+	fs.StringVar(&o.to, "to", "", "Reparse only pages fetched on or before this date (YYYY-MM-DD)")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Show the changes without saving them")
+	fs.Float64Var(&o.minComplete, "min-completeness", validate.DefaultMinCompleteness, "Completeness (0-1) below which an idea's report flags it")
+	return fs
+}
+
+// runReparse parses archived pages again with the current extractors,
+// printing what changed in each idea against its saved JSON and, unless
+// -dry-run is set, saving the new extraction in its place. Pages are
+// selected by -slug and by the -from and -to fetch dates; with neither,
+// every archived idea is reparsed.
+func runReparse(args []string) {
+	o := &reparseOptions{}
+	fs := o.flags()
+	fs.Parse(args)
+
+	first, err := parseDay(o.from)
+	if err != nil {
+		log.Fatalf("Invalid -from: %v", err)
+	}
+	last, err := parseDay(o.to)
+	if err != nil {
+		log.Fatalf("Invalid -to: %v", err)
+	}
+	if !last.IsZero() {
+		// Include the whole of the last day
+		last = last.AddDate(0, 0, 1)
+	}
+	if o.archive == "" {
+		o.archive = filepath.Join(o.dir, "archive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a, err := storage.OpenArchive(o.archive)
+	if err != nil {
+		log.Fatalf("Failed to open archive: %v", err)
+	}
+	entries, err := a.Entries()
+	if err != nil {
+		log.Fatalf("Failed to read archive: %v", err)
+	}
+	if len(entries) == 0 {
+		// Most likely the wrong directory rather than nothing to do
+		log.Fatalf("Archive %s holds no pages: pass the scraper's -archive directory with -archive", o.archive)
+	}
+	var selected []storage.ArchiveEntry
+	for _, e := range entries {
+		if o.slug != "" && e.Slug != o.slug {
+			continue
+		}
+		if !first.IsZero() && e.FetchedAt.Before(first) {
+			continue
+		}
+		if !last.IsZero() && !e.FetchedAt.Before(last) {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	ideas, err := scraper.ArchivedIdeas(a, selected)
+	if err != nil {
+		log.Fatalf("Failed to load archived pages: %v", err)
+	}
+	if len(ideas) == 0 {
+		log.Printf("No archived pages match in %s", o.archive)
+		return
+	}
+
+	store := &scraper.Store{MinCompleteness: o.minComplete}
+	if !o.dryRun && o.db != "" {
+		if err := os.MkdirAll(filepath.Dir(o.db), 0755); err != nil {
+			log.Fatalf("Failed to create database directory: %v", err)
+		}
+		if store.DB, err = storage.OpenSQLite(ctx, o.db); err != nil {
+			log.Fatalf("Failed to open database: %v", err)
+		}
+		defer store.DB.Close()
+	}
+
+	changed, failed := 0, 0
+	for _, idea := range ideas {
+		changes, err := scraper.Reparse(ctx, store, idea, o.dir, o.dryRun)
+		if err != nil {
+			log.Printf("ERROR: %s: %v", idea.Slug, err)
+			failed++
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		if len(changes) == 0 {
+			fmt.Printf("%s: unchanged\n", idea.Slug)
+			continue
+		}
+		changed++
+		fmt.Printf("%s: %d changes\n", idea.Slug, len(changes))
+		for _, c := range changes {
+			fmt.Printf("  %s\n", c)
+		}
+	}
+
+	verb := "Reparsed"
+	if o.dryRun {
+		verb = "Checked"
+	}
+	log.Printf("%s %d ideas, %d changed", verb, len(ideas)-failed, changed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseDay parses a YYYY-MM-DD date, returning the zero time for "".
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// upgradeOptions holds the flags of the upgrade subcommand.
+type upgradeOptions struct {
+	dir    string
+	dryRun bool
+}
+
+// flags returns the upgrade subcommand's flag set, parsing into o.
+func (o *upgradeOptions) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	fs.StringVar(&o.dir, "dir", "data/json", "Directory of JSON files to upgrade when no files are given")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Report which files would be upgraded without rewriting them")
+	return fs
+}
+
+// runUpgrade rewrites JSON files written by older versions of the scraper in
+// the current schema. With no file arguments every *.json in -dir is
+// checked; files already current are left untouched.
+func runUpgrade(args []string) {
+	o := &upgradeOptions{}
+	fs := o.flags()
+	fs.Parse(args)
+
+	files := fs.Args()
+	if len(files) == 0 {
+		var err error
+		files, err = filepath.Glob(filepath.Join(o.dir, "*.json"))
+		if err != nil {
+			log.Fatalf("Failed to list JSON files: %v", err)
+		}
+	}
+
+	upgraded, failed := 0, 0
+	for _, file := range files {
+		changed, err := upgradeFile(file, o.dryRun)
+		if err != nil {
+			log.Printf("ERROR: %s: %v", filepath.Base(file), err)
+			failed++
+			continue
+		}
+		if changed {
+			upgraded++
+			log.Printf("Upgraded: %s", filepath.Base(file))
+		}
+	}
+
+	log.Printf("Upgraded %d of %d files to schema version %d", upgraded, len(files), model.SchemaVersion)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// upgradeFile rewrites file in the current schema if it is older, keeping
+// its permissions. It reports whether the file needed upgrading.
+func upgradeFile(file string, dryRun bool) (bool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	idea, upgraded, err := model.Upgrade(data)
+	if err != nil || !upgraded || dryRun {
+		return upgraded, err
+	}
+
+	out, err := json.MarshalIndent(idea, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return false, err
+	}
+
+	// Write next to the original and rename, so an interrupted run never
+	// leaves a truncated file
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, out, info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp, file)
+}
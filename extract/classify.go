@@ -0,0 +1,183 @@
+package extract
+
+import (
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// notFoundPhrases mark a "not found" page rendered with status 200 when
+// they appear in its title or main headings.
+var notFoundPhrases = []string{
+	"404",
+	"not found",
+	"could not be found",
+	"couldn't find",
+	"does not exist",
+	"doesn't exist",
+}
+
+// loginPhrases mark a sign-in wall in the main content of a page.
+var loginPhrases = []string{
+	"sign in to",
+	"log in to",
+	"login to",
+	"please sign in",
+	"please log in",
+	"you must be logged in",
+	"you need to be logged in",
+	"create a free account to",
+}
+
+// upgradePhrases mark an upgrade prompt in the main content of a page.
+var upgradePhrases = []string{
+	"upgrade to",
+	"upgrade your plan",
+	"upgrade now",
+	"unlock this",
+	"unlock full access",
+	"members only",
+	"available on the pro",
+	"available to pro",
+	"subscribe to unlock",
+}
+
+// Classify returns the model.Access class of every page, keyed like pages.
+func Classify(pages map[string]string) map[string]string {
+	classes := make(map[string]string, len(pages))
+	for key, page := range pages {
+		classes[key] = ClassifyPage(key, page)
+	}
+	return classes
+}
+
+// Accessible returns the pages whose class is model.AccessOK.
+func Accessible(pages map[string]string, classes map[string]string) map[string]string {
+	ok := make(map[string]string, len(pages))
+	for key, page := range pages {
+		if classes[key] == model.AccessOK {
+			ok[key] = page
+		}
+	}
+	return ok
+}
+
+// ClassifyPage tells whether the page stored under key shows its content
+// or a wall in its place. A page with the section markers its extractors
+// look for is always model.AccessOK, whatever banners surround them; one
+// without is a soft 404 when its title or headings say so, and otherwise
+// behind a sign-in or upgrade wall when its main content asks for one.
+// Navigation, header and footer are ignored, as they carry "Sign in" and
+// "Upgrade" links on every page.
+func ClassifyPage(key, page string) string {
+	if hasSections(key, page) {
+		return model.AccessOK
+	}
+	doc := parseDocument(page)
+
+	var headings []string
+	for _, n := range doc.nodes {
+		if n.Type == html.ElementNode && (n.Data == "title" || n.Data == "h1" || n.Data == "h2") {
+			headings = append(headings, strings.ToLower(nodeText(n)))
+		}
+	}
+	if containsAny(strings.Join(headings, "\n"), notFoundPhrases) {
+		return model.AccessNotFound
+	}
+
+	text := strings.ToLower(mainText(doc.root))
+	switch {
+	case hasPasswordInput(doc) || containsAny(text, loginPhrases):
+		return model.AccessAuthRequired
+	case containsAny(text, upgradePhrases):
+		return model.AccessPlanRestricted
+	}
+	return model.AccessOK
+}
+
+// hasSections reports whether page has the section markers of its key,
+// either in the embedded payload or in the HTML: the anchors of
+// Anchors(key), the idea's date or tags on the overview page, and headed
+// blocks on the detail pages.
+func hasSections(key, page string) bool {
+	if p := decodePayload(page); p != nil && payloadSections(p, key) {
+		return true
+	}
+	if len(Anchors(key)) > 0 {
+		return len(Fingerprint(key, page).Anchors) > 0
+	}
+	if key == "/idea-of-the-day" {
+		return Meta(page).Date.Value != "" || len(Tags(page)) > 0
+	}
+	return len(pageBlocks(nil, page)) > 0
+}
+
+// payloadSections reports whether the payload holds the data Parse reads
+// from it for the page stored under key.
+func payloadSections(p *payload, key string) bool {
+	scratch := &model.FrameworkData{}
+	switch key {
+	case "/idea-of-the-day":
+		return p.object(func(m map[string]interface{}) bool {
+			return p.str(field(m, "slug")) != "" && p.str(field(m, "title", "name")) != ""
+		}) != nil
+	case "acp":
+		return p.acp() != nil || p.acpScores(scratch)
+	case "value-equation":
+		return p.valueEquation(scratch)
+	case "value-matrix":
+		return p.marketMatrix(scratch)
+	case "value-ladder":
+		return p.valueLadder(scratch)
+	}
+	return len(p.blocks(path.Base(key))) > 0
+}
+
+// mainText returns the visible text of n without its nav, header and footer
+// elements.
+func mainText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "nav", "header", "footer":
+				return
+			}
+		}
+		if skipElement(n) {
+			return
+		}
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// hasPasswordInput reports whether the page holds a login form.
+func hasPasswordInput(d *document) bool {
+	for _, n := range d.nodes {
+		if n.Type == html.ElementNode && n.Data == "input" && strings.EqualFold(attr(n, "type"), "password") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(s string, phrases []string) bool {
+	for _, p := range phrases {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
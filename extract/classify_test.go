@@ -0,0 +1,51 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+func TestClassifyCorpora(t *testing.T) {
+	for _, c := range corpora {
+		_, pages := loadCorpus(t, c.dir)
+		for key, class := range Classify(pages) {
+			if class != model.AccessOK {
+				t.Errorf("%s %s: class = %s, want ok", c.dir, key, class)
+			}
+		}
+	}
+}
+
+func TestClassifyPage(t *testing.T) {
+	const (
+		nav  = `<nav><a href="/login">Sign in to IdeaBrowser</a><a href="/pricing">Upgrade to Pro</a></nav>`
+		foot = `<footer>Members only perks. Log in to your account.</footer>`
+	)
+	for _, tt := range []struct {
+		name, key, page, want string
+	}{
+		{"login form", "acp",
+			`<html><body>` + nav + `<main><h1>Welcome back</h1><form><input type="email"><input type="PASSWORD"></form></main>` + foot + `</body></html>`,
+			model.AccessAuthRequired},
+		{"login prompt", "why-now",
+			`<html><body>` + nav + `<main><p>Please sign in to see this analysis.</p></main></body></html>`,
+			model.AccessAuthRequired},
+		{"upgrade prompt", "value-ladder",
+			`<html><body>` + nav + `<main><h2>This section is locked</h2><p>Upgrade to Pro to unlock the value ladder.</p></main>` + foot + `</body></html>`,
+			model.AccessPlanRestricted},
+		{"soft 404", "/idea-of-the-day",
+			`<html><head><title>Page Not Found | IdeaBrowser</title></head><body>` + nav + `<main><h1>Oops</h1><p>Please log in to browse more ideas.</p></main></body></html>`,
+			model.AccessNotFound},
+		{"banners around content", "value-matrix",
+			`<html><body><main><p>Upgrade to Pro to unlock every analysis.</p><h1>Market Matrix Analysis</h1><p>Uniqueness</p></main></body></html>`,
+			model.AccessOK},
+		{"walls only in navigation", "market-gap",
+			`<html><body>` + nav + `<main><p>Nothing here yet.</p></main>` + foot + `</body></html>`,
+			model.AccessOK},
+	} {
+		if got := ClassifyPage(tt.key, tt.page); got != tt.want {
+			t.Errorf("%s: ClassifyPage = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
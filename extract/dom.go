@@ -0,0 +1,272 @@
+package extract
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// document is a parsed page with its nodes flattened in document order, so
+// that "the first X after Y" and "everything between two headings" can be
+// answered without depending on the exact bytes of the markup.
+type document struct {
+	root  *html.Node
+	nodes []*html.Node
+	index map[*html.Node]int
+}
+
+// span is a half-open range [from, to) of document node positions.
+type span struct {
+	from, to int
+}
+
+func parseDocument(src string) *document {
+	root, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		// html.Parse only fails on reader errors
+		root = &html.Node{Type: html.DocumentNode}
+	}
+
+	d := &document{root: root, index: make(map[*html.Node]int)}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		d.index[n] = len(d.nodes)
+		d.nodes = append(d.nodes, n)
+		if skipElement(n) {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return d
+}
+
+// skipElement reports whether n is a script or style element whose content
+// is never page text.
+func skipElement(n *html.Node) bool {
+	return n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style")
+}
+
+// all returns the whole document as a span.
+func (d *document) all() span {
+	return span{0, len(d.nodes)}
+}
+
+// text returns the visible text of the whole document.
+func (d *document) text() string {
+	return nodeText(d.root)
+}
+
+// contains reports whether the visible text of the document contains s.
+func (d *document) contains(s string) bool {
+	return strings.Contains(d.text(), s)
+}
+
+// end returns the position just past the last descendant of n.
+func (d *document) end(n *html.Node) int {
+	last := n
+	for last.LastChild != nil && !skipElement(last) {
+		last = last.LastChild
+	}
+	return d.index[last] + 1
+}
+
+// after returns the span from just past n's subtree to the end of s.
+func (d *document) after(n *html.Node, s span) span {
+	return span{d.end(n), s.to}
+}
+
+// first returns the first element in s that satisfies match.
+func (d *document) first(s span, match func(*html.Node) bool) *html.Node {
+	for i := s.from; i < s.to && i < len(d.nodes); i++ {
+		if n := d.nodes[i]; n.Type == html.ElementNode && match(n) {
+			return n
+		}
+	}
+	return nil
+}
+
+// each calls fn for every element in s that satisfies match.
+func (d *document) each(s span, match func(*html.Node) bool, fn func(*html.Node)) {
+	for i := s.from; i < s.to && i < len(d.nodes); i++ {
+		if n := d.nodes[i]; n.Type == html.ElementNode && match(n) {
+			fn(n)
+		}
+	}
+}
+
+// heading returns the position of the first text node in s that contains
+// label, or -1.
+func (d *document) heading(s span, label string) int {
+	for i := s.from; i < s.to && i < len(d.nodes); i++ {
+		if n := d.nodes[i]; n.Type == html.TextNode && strings.Contains(n.Data, label) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sections splits the document at the first occurrence of each heading text.
+// Each found heading's section runs to the next found heading, or to the end
+// of the document for the last one. Missing headings have no entry.
+func (d *document) sections(headings ...string) map[string]span {
+	type mark struct {
+		name string
+		pos  int
+	}
+	var marks []mark
+	for _, h := range headings {
+		if pos := d.heading(d.all(), h); pos >= 0 {
+			marks = append(marks, mark{h, pos})
+		}
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].pos < marks[j].pos })
+
+	result := make(map[string]span)
+	for i, m := range marks {
+		to := len(d.nodes)
+		if i+1 < len(marks) {
+			to = marks[i+1].pos
+		}
+		result[m.name] = span{m.pos + 1, to}
+	}
+	return result
+}
+
+// labelled returns the first element in s whose text is exactly label.
+func (d *document) labelled(s span, label string) *html.Node {
+	return d.first(s, func(n *html.Node) bool { return nodeText(n) == label })
+}
+
+// labelValue returns the text that belongs to a label element in s: the
+// label's next sibling element, or the next paragraph when the label has no
+// sibling.
+func (d *document) labelValue(s span, label string) string {
+	n := d.labelled(s, label)
+	if n == nil {
+		return ""
+	}
+	if sib := nextElementSibling(n); sib != nil {
+		return nodeText(sib)
+	}
+	if p := d.first(d.after(n, s), isTag("p")); p != nil {
+		return nodeText(p)
+	}
+	return ""
+}
+
+// selectIn returns the elements in s matching sel, in document order.
+func (d *document) selectIn(s span, sel cascadia.Matcher) []*html.Node {
+	var out []*html.Node
+	d.each(s, func(n *html.Node) bool { return sel.Match(n) }, func(n *html.Node) {
+		out = append(out, n)
+	})
+	return out
+}
+
+// firstIn returns the first element in s matching sel.
+func (d *document) firstIn(s span, sel cascadia.Matcher) *html.Node {
+	return d.first(s, sel.Match)
+}
+
+// nodeText returns the visible text of n. Element and comment boundaries
+// count as whitespace, and runs of whitespace collapse to a single space.
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+		case html.CommentNode:
+			b.WriteByte(' ')
+		case html.ElementNode:
+			if skipElement(n) {
+				return
+			}
+			b.WriteByte(' ')
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+			b.WriteByte(' ')
+		default:
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// plainText returns the text of n when n contains nothing but text.
+func plainText(n *html.Node) (string, bool) {
+	if n.FirstChild == nil {
+		return "", false
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.TextNode {
+			return "", false
+		}
+	}
+	return nodeText(n), true
+}
+
+// leadingText returns the text that opens n, before its first child element
+// or comment.
+func leadingText(n *html.Node) string {
+	if c := n.FirstChild; c != nil && c.Type == html.TextNode {
+		return strings.Join(strings.Fields(c.Data), " ")
+	}
+	return ""
+}
+
+// nextElementSibling returns the next sibling element of n, or nil.
+func nextElementSibling(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+// adjacentElement returns the element directly following n, separated from
+// it by nothing but whitespace, or nil.
+func adjacentElement(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		switch {
+		case s.Type == html.ElementNode:
+			return s
+		case s.Type == html.TextNode && strings.TrimSpace(s.Data) == "":
+			continue
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func isTag(tag string) func(*html.Node) bool {
+	return func(n *html.Node) bool { return n.Data == tag }
+}
+
+var scoreRe = regexp.MustCompile(`^(\d+)\s*/\s*10$`)
+
+// isScore matches elements reading "N/10" (React renders "N<!-- -->/10").
+func isScore(n *html.Node) bool {
+	return scoreRe.MatchString(nodeText(n))
+}
+
+// scoreValue returns N from an "N/10" element.
+func scoreValue(n *html.Node) string {
+	if m := scoreRe.FindStringSubmatch(nodeText(n)); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,480 @@
+// Package extract turns scraped IdeaBrowser HTML into model structures.
+//
+// Pages are parsed into a DOM and fields are found by their visible labels
+// and section headings rather than by exact markup, so changes to Tailwind
+// classes or React hydration comments do not break extraction.
+package extract
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+var (
+	badgeSel      = cascadia.MustCompile(`div[class*="rounded-full"]`)
+	mutedParaSel  = cascadia.MustCompile(`p[class*="text-gray-600"]`)
+	highlightSel  = cascadia.MustCompile(`[class*="bg-yellow-50"] h3`)
+	priceSel      = cascadia.MustCompile(`span[class*="bg-blue-50"]`)
+	fontMediumSel = cascadia.MustCompile(`span[class*="font-medium"]`)
+
+	dateRe = regexp.MustCompile(`(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\s+\d{1,2},\s+\d{4}`)
+
+	// acpScoreRes find the first "N/10" following each ACP score's name
+	acpScoreRes = map[string]*regexp.Regexp{
+		"Audience":  regexp.MustCompile(`Audience.*?(\d+)\s*/\s*10`),
+		"Community": regexp.MustCompile(`Community.*?(\d+)\s*/\s*10`),
+		"Product":   regexp.MustCompile(`Product.*?(\d+)\s*/\s*10`),
+	}
+)
+
+// TextBetween extracts text between two strings
+func TextBetween(html, start, end string) string {
+	startIdx := strings.Index(html, start)
+	if startIdx == -1 {
+		return ""
+	}
+	startIdx += len(start)
+
+	endIdx := strings.Index(html[startIdx:], end)
+	if endIdx == -1 {
+		return ""
+	}
+
+	return strings.TrimSpace(html[startIdx : startIdx+endIdx])
+}
+
+// CleanHTMLText removes HTML tags, script and style content and entities
+// from an HTML fragment and collapses whitespace
+func CleanHTMLText(text string) string {
+	return parseDocument(text).text()
+}
+
+// IdeaInfo extracts the idea's title, description and date from the HTML.
+// See Meta for how candidates are ranked.
+func IdeaInfo(page string) (string, string, string) {
+	meta := Meta(page)
+	return meta.Title.Value, meta.Description.Value, meta.Date.Value
+}
+
+// Tags extracts tags/badges from the HTML
+func Tags(page string) []string {
+	doc := parseDocument(page)
+	tags := []string{}
+	tagSet := make(map[string]bool)
+
+	// Look for badge/pill elements and take the label span inside each
+	for _, badge := range doc.selectIn(doc.all(), badgeSel) {
+		label := doc.first(span{doc.index[badge] + 1, doc.end(badge)}, func(n *html.Node) bool {
+			_, ok := plainText(n)
+			return n.Data == "span" && ok
+		})
+		if label == nil {
+			continue
+		}
+		tag := nodeText(label)
+		if tag != "" && !tagSet[tag] && len(tag) < 50 {
+			tagSet[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// acpSection is a section of the ACP page: its heading, the keys it may
+// have in a payload, and the field each of its labels fills.
+type acpSection struct {
+	heading     string
+	payloadKeys []string
+	fields      []labelledField
+}
+
+// labelledField is a page label and the field its value is stored in. The
+// label doubles as a payload key once normalized, so "Content Gaps" also
+// matches contentGaps.
+type labelledField struct {
+	label string
+	dst   *string
+}
+
+// acpSections returns the sections of the ACP page filling acp.
+func acpSections(acp *model.ACPData) []acpSection {
+	return []acpSection{
+		{"AUDIENCE ANALYSIS", []string{"audienceAnalysis", "audience"}, []labelledField{
+			{"Demographics", &acp.Audience.Demographics},
+			{"Psychographics", &acp.Audience.Psychographics},
+			{"Platforms", &acp.Audience.Platforms},
+			{"Unmet Needs", &acp.Audience.UnmetNeeds},
+			{"Content Gaps", &acp.Audience.ContentGaps},
+			{"Differentiation", &acp.Audience.Differentiation},
+			{"Secret Sauce", &acp.Audience.SecretSauce},
+			{"Key Topics", &acp.Audience.KeyTopics},
+			{"Content Formats", &acp.Audience.ContentFormats},
+		}},
+		{"COMMUNITY ANALYSIS", []string{"communityAnalysis", "community"}, []labelledField{
+			{"Primary Platform", &acp.Community.PrimaryPlatform},
+			{"Platform Rationale", &acp.Community.PlatformRationale},
+			{"Secondary Platforms", &acp.Community.SecondaryPlatforms},
+			{"UGC Strategy", &acp.Community.UGCStrategy},
+			{"Moderation Approach", &acp.Community.ModerationApproach},
+			{"Transparency", &acp.Community.Transparency},
+			{"Community Rituals", &acp.Community.CommunityRituals},
+			{"Content Calendar", &acp.Community.ContentCalendar},
+			{"Interaction Methods", &acp.Community.InteractionMethods},
+		}},
+		{"PRODUCT ANALYSIS", []string{"productAnalysis", "product"}, []labelledField{
+			{"Description", &acp.Product.Description},
+			{"Key Features", &acp.Product.KeyFeatures},
+			{"Value Proposition", &acp.Product.ValueProposition},
+			{"MVP", &acp.Product.MVP},
+			{"Future Iterations", &acp.Product.FutureIterations},
+			{"Community Integration", &acp.Product.CommunityIntegration},
+			{"Network Effects", &acp.Product.NetworkEffects},
+			{"Sticky Features", &acp.Product.StickyFeatures},
+			{"Usage Frequency", &acp.Product.UsageFrequency},
+		}},
+		{"EXECUTION PLAN", []string{"executionPlan", "execution"}, []labelledField{
+			{"90-Day Plan", &acp.ExecutionPlan.NinetyDayPlan},
+		}},
+	}
+}
+
+// ACP extracts the Audience, Community, Product analysis from the ACP page
+func ACP(page string) *model.ACPData {
+	acp := &model.ACPData{}
+	doc := parseDocument(page)
+
+	// Check if this is the ACP Framework page
+	if !doc.contains("ACP Framework Analysis") {
+		return acp
+	}
+
+	sections := acpSections(acp)
+	headings := make([]string, len(sections))
+	for i, section := range sections {
+		headings[i] = section.heading
+	}
+	spans := doc.sections(headings...)
+
+	for _, section := range sections {
+		s, ok := spans[section.heading]
+		if !ok {
+			continue
+		}
+		for _, f := range section.fields {
+			*f.dst = doc.labelValue(s, f.label)
+		}
+	}
+
+	return acp
+}
+
+// Framework extracts Framework Fit metrics from the HTML
+func Framework(page string) *model.FrameworkData {
+	framework := &model.FrameworkData{}
+	doc := parseDocument(page)
+
+	// Check if this is the Value Equation page
+	if doc.contains("Value Equation Analysis") {
+		extractValueEquation(doc, framework)
+	}
+
+	// Check if this is the Market Matrix page
+	if doc.contains("Market Matrix Analysis") {
+		extractMarketMatrix(doc, framework)
+	}
+
+	// Check if this is the ACP Framework page
+	if doc.contains("ACP Framework Analysis") {
+		extractACPScores(doc, framework)
+	}
+
+	// Check if this is the Value Ladder page
+	if doc.contains("Value Ladder Strategy") {
+		extractValueLadder(doc, framework)
+	}
+
+	return framework
+}
+
+// mutedParagraphAfter returns the text of the first grey body paragraph
+// after n, falling back to the first paragraph after n.
+func mutedParagraphAfter(doc *document, n *html.Node, s span) string {
+	rest := doc.after(n, s)
+	p := doc.first(rest, func(p *html.Node) bool {
+		_, ok := plainText(p)
+		return mutedParaSel.Match(p) && ok
+	})
+	if p == nil {
+		p = doc.first(rest, func(p *html.Node) bool {
+			_, ok := plainText(p)
+			return p.Data == "p" && ok
+		})
+	}
+	if p == nil {
+		return ""
+	}
+	return nodeText(p)
+}
+
+func extractValueEquation(doc *document, framework *model.FrameworkData) {
+	// Extract overall rating
+	if label := doc.labelled(doc.all(), "Overall Rating"); label != nil {
+		rating := doc.first(doc.after(label, doc.all()), func(n *html.Node) bool {
+			_, err := strconv.Atoi(nodeText(n))
+			return err == nil
+		})
+		if rating != nil {
+			framework.ValueEquation.Score, _ = strconv.Atoi(nodeText(rating))
+		}
+	}
+
+	// Extract individual component scores and descriptions
+	for _, c := range valueComponents {
+		heading := doc.labelled(doc.all(), c.name)
+		if heading == nil {
+			continue
+		}
+		score := doc.first(doc.after(heading, doc.all()), isScore)
+		if score == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(scoreValue(score))
+		framework.ValueEquation.Components = append(framework.ValueEquation.Components, model.ValueComponent{
+			Name:        c.name,
+			Score:       n,
+			MaxScore:    10,
+			Description: mutedParagraphAfter(doc, score, doc.all()),
+		})
+	}
+
+	framework.ValueEquation.Rating = rating(framework.ValueEquation.Score)
+}
+
+// valueComponents are the four parts of the value equation, by heading,
+// with the shorter keys payloads also use for them.
+var valueComponents = []struct {
+	name string
+	keys []string
+}{
+	{model.DreamOutcome, []string{"dream"}},
+	{model.PerceivedLikelihood, []string{"likelihood"}},
+	{model.TimeDelay, nil},
+	{model.EffortSacrifice, []string{"effort", "effortAndSacrifice"}},
+}
+
+// rating turns a 0-10 value equation score into a rating label.
+func rating(score int) string {
+	switch {
+	case score >= 8:
+		return "Excellent"
+	case score >= 6:
+		return "Good"
+	case score >= 4:
+		return "Fair"
+	default:
+		return "Poor"
+	}
+}
+
+var quadrants = []string{"Category King", "Tech Novelty", "Commodity Play", "Low Impact"}
+
+func extractMarketMatrix(doc *document, framework *model.FrameworkData) {
+	// Extract Uniqueness and Value scores
+	for _, s := range []struct {
+		label  string
+		target *string
+	}{
+		{"Uniqueness", &framework.MarketMatrix.Uniqueness},
+		{"Value", &framework.MarketMatrix.Value},
+	} {
+		label := doc.labelled(doc.all(), s.label)
+		if label == nil {
+			continue
+		}
+		if score := doc.first(doc.after(label, doc.all()), isScore); score != nil {
+			*s.target = scoreValue(score) + "/10"
+		}
+	}
+
+	// Extract main analysis paragraph
+	if heading := doc.labelled(doc.all(), "Market Matrix Analysis"); heading != nil {
+		framework.MarketMatrix.Description = mutedParagraphAfter(doc, heading, doc.all())
+	}
+
+	sections := doc.sections("Position Analysis", "Understanding the Quadrants")
+
+	// Extract position - the heading of the highlighted quadrant
+	if h3 := doc.firstIn(doc.all(), highlightSel); h3 != nil {
+		framework.MarketMatrix.Position = nodeText(h3)
+	}
+
+	// If position not found, look for the position badge
+	if framework.MarketMatrix.Position == "" {
+		if analysis, ok := sections["Position Analysis"]; ok {
+			badge := doc.first(analysis, func(n *html.Node) bool {
+				return n.Data == "span" && isQuadrant(nodeText(n))
+			})
+			if badge != nil {
+				framework.MarketMatrix.Position = nodeText(badge)
+			}
+		}
+	}
+
+	// Extract position explanation
+	if position := framework.MarketMatrix.Position; position != "" {
+		badge := doc.first(doc.all(), func(n *html.Node) bool {
+			return n.Data == "span" && nodeText(n) == position
+		})
+		if badge != nil {
+			explanation := doc.first(doc.after(badge, doc.all()), func(n *html.Node) bool {
+				text, ok := plainText(n)
+				return n.Data == "p" && ok && text != ""
+			})
+			if explanation != nil && framework.MarketMatrix.Description != "" {
+				framework.MarketMatrix.Description += "\n\nPosition Analysis: " + nodeText(explanation)
+			}
+		}
+	}
+
+	// Extract quadrant descriptions for context
+	if guide, ok := sections["Understanding the Quadrants"]; ok {
+		quadrantDetails := ""
+		for _, name := range quadrants {
+			if detail := doc.labelValue(guide, name); detail != "" {
+				quadrantDetails += "\n\n" + name + ": " + detail
+			}
+		}
+
+		if quadrantDetails != "" && framework.MarketMatrix.Description != "" {
+			framework.MarketMatrix.Description += quadrantDetails
+		}
+	}
+}
+
+func isQuadrant(text string) bool {
+	for _, q := range quadrants {
+		if strings.Contains(text, q) {
+			return true
+		}
+	}
+	return false
+}
+
+func extractACPScores(doc *document, framework *model.FrameworkData) {
+	// Look for scores in the main page summary (if present)
+	acpScores := []struct {
+		name  string
+		score *int
+	}{
+		{"Audience", &framework.ACPFramework.Audience},
+		{"Community", &framework.ACPFramework.Community},
+		{"Product", &framework.ACPFramework.Product},
+	}
+
+	text := doc.text()
+	for _, s := range acpScores {
+		// Prefer the score card labelled with the name
+		if label := doc.labelled(doc.all(), s.name); label != nil {
+			if score := doc.first(doc.after(label, doc.all()), isScore); score != nil {
+				*s.score, _ = strconv.Atoi(scoreValue(score))
+				continue
+			}
+		}
+
+		// Fall back to the first "N/10" following the name anywhere in the text
+		if matches := acpScoreRes[s.name].FindStringSubmatch(text); len(matches) > 1 {
+			*s.score, _ = strconv.Atoi(matches[1])
+		}
+	}
+
+	// Calculate overall ACP score
+	if framework.ACPFramework.Audience > 0 && framework.ACPFramework.Community > 0 && framework.ACPFramework.Product > 0 {
+		framework.ACPFramework.Overall = (framework.ACPFramework.Audience + framework.ACPFramework.Community + framework.ACPFramework.Product) / 3
+	}
+}
+
+// ladderStages are the value ladder section headings and the stage kind
+// each introduces, lowest offer first.
+var ladderStages = []struct{ heading, kind string }{
+	{"LEAD MAGNET", model.LeadMagnet},
+	{"FRONTEND OFFER", model.FrontendOffer},
+	{"CORE OFFER", model.CoreOffer},
+	{"CONTINUITY PROGRAM", model.ContinuityProgram},
+	{"BACKEND OFFER", model.BackendOffer},
+}
+
+func extractValueLadder(doc *document, framework *model.FrameworkData) {
+	headings := make([]string, len(ladderStages))
+	for i, s := range ladderStages {
+		headings[i] = s.heading
+	}
+
+	// Find the stage sections to extract
+	sections := doc.sections(headings...)
+
+	var stages model.LadderStages
+	for _, s := range ladderStages {
+		section, ok := sections[s.heading]
+		if !ok {
+			continue
+		}
+		stage := model.LadderStage{Kind: s.kind}
+
+		// Extract title and the description that follows it
+		if title := doc.first(section, isTag("h1")); title != nil {
+			stage.Title = nodeText(title)
+			stage.Description = mutedParagraphAfter(doc, title, section)
+		}
+
+		// Extract price
+		if price := doc.firstIn(section, priceSel); price != nil {
+			stage.Price = model.ParsePrice(nodeText(price))
+		}
+
+		stage.ValueProvided = doc.labelValue(section, "Value Provided")
+		stage.Goal = doc.labelValue(section, "Goal")
+
+		stages = append(stages, stage)
+	}
+
+	if len(stages) > 0 {
+		framework.ValueLadderStages = stages
+		framework.ValueLadderSummary = model.SummarizeLadder(stages)
+	}
+}
+
+// PageData extracts key-value data from a page: headings followed directly
+// by a value element, and font-medium label spans followed by a value span
+func PageData(page string) map[string]string {
+	data := make(map[string]string)
+	doc := parseDocument(page)
+
+	add := func(labelNode *html.Node, valueTag string) {
+		key, ok := plainText(labelNode)
+		if !ok {
+			return
+		}
+		next := adjacentElement(labelNode)
+		if next == nil || (valueTag != "" && next.Data != valueTag) {
+			return
+		}
+		value := leadingText(next)
+		if key != "" && value != "" && len(key) < 50 {
+			data[key] = value
+		}
+	}
+
+	doc.each(doc.all(), isTag("h3"), func(n *html.Node) { add(n, "") })
+	for _, n := range doc.selectIn(doc.all(), fontMediumSel) {
+		add(n, "span")
+	}
+
+	return data
+}
@@ -0,0 +1,277 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// corpora are the saved pages under testdata, one directory per idea, in
+// the page_N.html layout written by -save-html.
+var corpora = []struct {
+	dir  string
+	slug string
+}{
+	{"nook", "nook-smart-reading-nooks"},
+	// Pages carrying a Next.js payload (__NEXT_DATA__ and flight chunks)
+	{"payload", "nook"},
+	// An overview page with JSON-LD, OpenGraph and a generic <title>
+	{"meta", "calmdesk-focus-pods"},
+	// Detail pages with lists, links, repeated and unknown headings
+	{"detail", "calmdesk-focus-pods"},
+}
+
+// testSite is the site the corpora were saved from.
+var testSite, _ = url.Parse("https://www.ideabrowser.com")
+
+// pageKeys is the page key of page_N.html, in the order the scraper
+// fetches pages (see scraper.PageURLs).
+var pageKeys = []string{
+	"/idea-of-the-day",
+	"acp",
+	"value-equation",
+	"value-matrix",
+	"value-ladder",
+	"build/landing-page",
+	"founder-fit",
+	"why-now",
+	"proof-signals",
+	"market-gap",
+	"execution-plan",
+}
+
+// loadCorpus returns the pages of a corpus keyed by file name and by page
+// key.
+func loadCorpus(t *testing.T, dir string) (files map[string]string, pages map[string]string) {
+	t.Helper()
+	files = make(map[string]string)
+	pages = make(map[string]string)
+	for i, key := range pageKeys {
+		name := fmt.Sprintf("page_%d.html", i+1)
+		data, err := os.ReadFile(filepath.Join("testdata", dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[name] = string(data)
+		pages[key] = string(data)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no pages in testdata/%s", dir)
+	}
+	return files, pages
+}
+
+// checkGolden compares got, marshalled as indented JSON, with
+// testdata/golden/name.json, rewriting the file instead with -update.
+func checkGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("%s differs from golden file (run go test -update to accept):\n%s", path, lineDiff(string(want), string(data)))
+	}
+}
+
+// lineDiff lists the lines that differ between want and got.
+func lineDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			fmt.Fprintf(&b, "line %d:\n  want: %s\n  got:  %s\n", i+1, wl, gl)
+		}
+	}
+	return b.String()
+}
+
+// TestExtractors runs each extractor on the pages Parse applies it to, in
+// every corpus that has one of them.
+func TestExtractors(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string // nil for every page
+		extract func(page string) interface{}
+	}{
+		{"meta", []string{"/idea-of-the-day"}, func(page string) interface{} { return Meta(page) }},
+		{"tags", []string{"/idea-of-the-day"}, func(page string) interface{} { return Tags(page) }},
+		{"acp", []string{"acp"}, func(page string) interface{} { return ACP(page) }},
+		{"framework", []string{"acp", "value-equation", "value-matrix", "value-ladder"}, func(page string) interface{} { return Framework(page) }},
+		{"page_data", []string{"value-ladder"}, func(page string) interface{} { return PageData(page) }},
+		{"links", nil, func(page string) interface{} { return Links(testSite, page) }},
+	}
+
+	for _, corpus := range corpora {
+		_, pages := loadCorpus(t, corpus.dir)
+		for _, tt := range tests {
+			keys := tt.keys
+			if keys == nil {
+				keys = pageKeys
+			}
+			got := make(map[string]interface{})
+			for _, key := range keys {
+				if page, ok := pages[key]; ok {
+					got[key] = tt.extract(page)
+				}
+			}
+			if len(got) == 0 {
+				continue
+			}
+			t.Run(corpus.dir+"/"+tt.name, func(t *testing.T) {
+				checkGolden(t, corpus.dir+"_"+tt.name, got)
+			})
+		}
+	}
+}
+
+// TestDetailPages checks the typed detail pages Parse builds, from the
+// embedded payload or the HTML, for every corpus that has detail pages.
+func TestDetailPages(t *testing.T) {
+	fields := map[string]func(idea *model.IdeaData) interface{}{
+		"build/landing-page": func(idea *model.IdeaData) interface{} { return idea.BuildInfo },
+		"founder-fit":        func(idea *model.IdeaData) interface{} { return idea.FounderFit },
+		"why-now":            func(idea *model.IdeaData) interface{} { return idea.WhyNow },
+		"proof-signals":      func(idea *model.IdeaData) interface{} { return idea.ProofSignals },
+		"market-gap":         func(idea *model.IdeaData) interface{} { return idea.MarketGap },
+		"execution-plan":     func(idea *model.IdeaData) interface{} { return idea.ExecutionPlan },
+	}
+
+	for _, corpus := range corpora {
+		_, pages := loadCorpus(t, corpus.dir)
+		idea := Parse(testSite, corpus.slug, pages)
+		got := make(map[string]interface{})
+		for key, field := range fields {
+			if _, ok := pages[key]; ok {
+				got[key] = field(idea)
+			}
+		}
+		if len(got) == 0 {
+			continue
+		}
+		t.Run(corpus.dir, func(t *testing.T) {
+			checkGolden(t, corpus.dir+"_pages", got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, corpus := range corpora {
+		t.Run(corpus.dir, func(t *testing.T) {
+			_, pages := loadCorpus(t, corpus.dir)
+			checkGolden(t, corpus.dir+"_parse", Parse(testSite, corpus.slug, pages))
+		})
+	}
+}
+
+func TestIdeaSlugs(t *testing.T) {
+	listing := `<ul>
+<li><a href="/idea/first-idea">First</a> <a href="/idea/first-idea/acp">ACP</a></li>
+<li><a href="/idea/second-idea?ref=list">Second</a></li>
+<li><a href="/idea/third-idea#top">Third</a></li>
+<li><a href="/ideas">All ideas</a> <a href="/idea-of-the-day">Today</a></li>
+<li><a href="/idea/">Empty</a> <a href="/idea/Bad_Slug">Bad</a> <a href="/idea/double--hyphen">Bad</a></li>
+</ul>`
+	got := IdeaSlugs(listing)
+	if want := []string{"first-idea", "second-idea", "third-idea"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IdeaSlugs = %q, want %q", got, want)
+	}
+	if got := IdeaSlug(`<a href="/idea/Bad_Slug/acp">x</a>`); got != "" {
+		t.Errorf("IdeaSlug took %q, which the schema rejects", got)
+	}
+	if got := IdeaSlugs("<p>No ideas yet</p>"); len(got) != 0 {
+		t.Errorf("IdeaSlugs of a page without links = %q", got)
+	}
+}
+
+func FuzzTextBetween(f *testing.F) {
+	f.Add(`<h1 class="title">Nook</h1>`, `class="title">`, `</h1>`)
+	f.Add(`<p>unterminated`, `<p>`, `</p>`)
+	f.Add(``, ``, ``)
+	f.Add(`aaa`, `a`, `a`)
+	f.Fuzz(func(t *testing.T, html, start, end string) {
+		got := TextBetween(html, start, end)
+		if !strings.Contains(html, got) {
+			t.Errorf("TextBetween returned %q, which is not part of the input", got)
+		}
+	})
+}
+
+func FuzzCleanHTMLText(f *testing.F) {
+	f.Add(`<p>A &amp; B<!-- -->/10</p>`)
+	f.Add(`<script>var x = "<p>";</script><style>p{}</style>text`)
+	f.Add(`<div><span>unclosed <b>tags`)
+	f.Add(`</p></div>&#x27;&bogus;<<>>`)
+	f.Add("<table><tr><td>cell\x00</td></tr>")
+	for i := 1; i <= len(pageKeys); i++ {
+		if data, err := os.ReadFile(filepath.Join("testdata", "nook", fmt.Sprintf("page_%d.html", i))); err == nil {
+			f.Add(string(data))
+		}
+	}
+	f.Fuzz(func(t *testing.T, html string) {
+		got := CleanHTMLText(html)
+		if got != strings.TrimSpace(got) {
+			t.Errorf("CleanHTMLText(%q) = %q has surrounding whitespace", html, got)
+		}
+		if strings.Contains(got, "  ") {
+			t.Errorf("CleanHTMLText(%q) = %q has uncollapsed whitespace", html, got)
+		}
+	})
+}
+
+func FuzzDecodePayload(f *testing.F) {
+	f.Add(`<script>self.__next_f.push([1,"1:T-5,abc\n"])</script>`)
+	f.Add(`<script>self.__next_f.push([1,"1:Tffffffffffffffff,abc"])</script>`)
+	f.Add(`<script>self.__next_f.push([1,"0:[\"$L1\"]\n1:T3,abc2:{\"slug\":\"nook\"}\n"])</script>`)
+	f.Add(`<script id="__NEXT_DATA__" type="application/json">{"props":{"pageProps":{"idea":{"slug":"nook"}}}}</script>`)
+	for i := 1; i <= len(pageKeys); i++ {
+		if data, err := os.ReadFile(filepath.Join("testdata", "payload", fmt.Sprintf("page_%d.html", i))); err == nil {
+			f.Add(string(data))
+		}
+	}
+	f.Fuzz(func(t *testing.T, page string) {
+		if p := decodePayload(page); p != nil {
+			// Field lookups follow references between rows
+			p.lookup("idea", "valueEquation")
+			p.blocks("why-now")
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package extract
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// DefaultLayoutTolerance is the share of a page's shape that may change
+// before CompareLayout reports drift.
+const DefaultLayoutTolerance = 0.25
+
+// shapeDepth is the number of tag names in a Shape path.
+const shapeDepth = 3
+
+// Anchors returns the headings and labels the HTML extractors look for on
+// the page stored under key, or nil for pages read without fixed anchors.
+func Anchors(key string) []string {
+	switch key {
+	case "acp":
+		anchors := []string{"ACP Framework Analysis"}
+		for _, s := range acpSections(&model.ACPData{}) {
+			anchors = append(anchors, s.heading)
+			for _, f := range s.fields {
+				anchors = append(anchors, f.label)
+			}
+		}
+		return append(anchors, "Audience", "Community", "Product")
+	case "value-equation":
+		anchors := []string{"Value Equation Analysis", "Overall Rating"}
+		for _, c := range valueComponents {
+			anchors = append(anchors, c.name)
+		}
+		return anchors
+	case "value-matrix":
+		return []string{"Market Matrix Analysis", "Uniqueness", "Value", "Position Analysis", "Understanding the Quadrants"}
+	case "value-ladder":
+		anchors := []string{"Value Ladder Strategy"}
+		for _, s := range ladderStages {
+			anchors = append(anchors, s.heading)
+		}
+		return append(anchors, "Value Provided", "Goal")
+	}
+	return nil
+}
+
+// Fingerprint reduces the page stored under key to its structure: heading
+// and label texts, the anchors of Anchors(key) it has, and its DOM shape.
+func Fingerprint(key, page string) *model.Fingerprint {
+	doc := parseDocument(page)
+	fp := &model.Fingerprint{}
+	texts := make(map[string]bool)
+	shape := make(map[string]bool)
+
+	doc.each(doc.all(), func(n *html.Node) bool { return n.Type == html.ElementNode }, func(n *html.Node) {
+		if path := shapePath(n); path != "" {
+			shape[path] = true
+		}
+		text, ok := leafText(n)
+		if !ok || text == "" || len(text) >= 80 {
+			return
+		}
+		texts[text] = true
+		switch {
+		case isHeading(n):
+			fp.Headings = append(fp.Headings, text)
+		case fontMediumSel.Match(n) || isSectionLabel(text):
+			fp.Labels = append(fp.Labels, text)
+		}
+	})
+
+	for _, anchor := range Anchors(key) {
+		if hasAnchor(texts, anchor) {
+			fp.Anchors = append(fp.Anchors, anchor)
+		}
+	}
+	for path := range shape {
+		fp.Shape = append(fp.Shape, path)
+	}
+	sort.Strings(fp.Shape)
+	return fp
+}
+
+// leafText returns the text of n when n holds nothing but text and
+// comments, which React leaves between the parts of a rendered string.
+func leafText(n *html.Node) (string, bool) {
+	if n.FirstChild == nil {
+		return "", false
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.TextNode && c.Type != html.CommentNode {
+			return "", false
+		}
+	}
+	return nodeText(n), true
+}
+
+// isSectionLabel reports whether text reads like an upper-case section
+// label such as "AUDIENCE ANALYSIS".
+func isSectionLabel(text string) bool {
+	letters := false
+	for _, r := range text {
+		if unicode.IsLower(r) {
+			return false
+		}
+		letters = letters || unicode.IsUpper(r)
+	}
+	return letters && len(strings.Fields(text)) <= 5
+}
+
+// hasAnchor reports whether one of the page texts is anchor, or contains it
+// when anchor is more than a word.
+func hasAnchor(texts map[string]bool, anchor string) bool {
+	if texts[anchor] {
+		return true
+	}
+	if !strings.Contains(anchor, " ") {
+		return false
+	}
+	for text := range texts {
+		if strings.Contains(text, anchor) {
+			return true
+		}
+	}
+	return false
+}
+
+// shapePath returns the tag names of n and its closest ancestors, or "" for
+// elements inside SVG images, whose markup is artwork rather than layout.
+func shapePath(n *html.Node) string {
+	var tags []string
+	for a := n; a != nil && a.Type == html.ElementNode; a = a.Parent {
+		if a.Data == "svg" && a != n {
+			return ""
+		}
+		if len(tags) < shapeDepth {
+			tags = append(tags, a.Data)
+		}
+	}
+	for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
+		tags[i], tags[j] = tags[j], tags[i]
+	}
+	return strings.Join(tags, ">")
+}
+
+// Drift is how a page's layout moved away from its last known-good
+// fingerprint.
+type Drift struct {
+	Page string
+	// Similarity is the share of element paths the two shapes have in
+	// common, from 0 to 1.
+	Similarity float64
+	// Missing are the known-good anchors the page no longer has.
+	Missing []string
+	// Renamed maps missing anchors to the heading or label that took their
+	// place, when one did.
+	Renamed map[string]string
+}
+
+// CompareLayout compares the current fingerprint of the page stored under
+// key with its known-good one. It returns nil when every known-good anchor
+// is still there and the shapes differ by no more than tolerance.
+func CompareLayout(key string, known, current *model.Fingerprint, tolerance float64) *Drift {
+	d := &Drift{Page: key, Similarity: jaccard(known.Shape, current.Shape)}
+
+	have := make(map[string]bool)
+	for _, a := range current.Anchors {
+		have[a] = true
+	}
+	renamed := replacements(known.Headings, current.Headings)
+	for k, v := range replacements(known.Labels, current.Labels) {
+		renamed[k] = v
+	}
+	for _, a := range known.Anchors {
+		if have[a] {
+			continue
+		}
+		d.Missing = append(d.Missing, a)
+		if to, ok := renamed[a]; ok {
+			if d.Renamed == nil {
+				d.Renamed = make(map[string]string)
+			}
+			d.Renamed[a] = to
+		}
+	}
+
+	if len(d.Missing) == 0 && d.Similarity >= 1-tolerance {
+		return nil
+	}
+	return d
+}
+
+// String describes the drift in one line, naming the missing anchors.
+func (d *Drift) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "layout drift on %s: %.0f%% of the page structure unchanged", d.Page, d.Similarity*100)
+	for i, a := range d.Missing {
+		if i == 0 {
+			b.WriteString("; missing anchors: ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%q", a)
+		if to, ok := d.Renamed[a]; ok {
+			fmt.Fprintf(&b, " (renamed to %q?)", to)
+		}
+	}
+	return b.String()
+}
+
+// jaccard returns the share of the union of two sorted sets that is in
+// both; two empty sets are identical.
+func jaccard(a, b []string) float64 {
+	i, j, common := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 1
+	}
+	return float64(common) / float64(union)
+}
+
+// replacements diffs two text sequences and pairs the texts removed from
+// old with those inserted at the same place in new, in order.
+func replacements(old, new []string) map[string]string {
+	// lcs[i][j] is the longest common subsequence of old[i:] and new[j:]
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	out := make(map[string]string)
+	var removed, inserted []string
+	flush := func() {
+		for k := 0; k < len(removed) && k < len(inserted); k++ {
+			out[removed[k]] = inserted[k]
+		}
+		removed, inserted = nil, nil
+	}
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case i < len(old) && j < len(new) && old[i] == new[j]:
+			flush()
+			i++
+			j++
+		case j == len(new) || (i < len(old) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, old[i])
+			i++
+		default:
+			inserted = append(inserted, new[j])
+			j++
+		}
+	}
+	flush()
+	return out
+}
@@ -0,0 +1,73 @@
+package extract
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readPage(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFingerprintAnchors(t *testing.T) {
+	for _, tt := range []struct{ key, file string }{
+		{"acp", "page_2.html"},
+		{"value-equation", "page_3.html"},
+		{"value-matrix", "page_4.html"},
+		{"value-ladder", "page_5.html"},
+	} {
+		fp := Fingerprint(tt.key, readPage(t, "testdata/nook/"+tt.file))
+		if !reflect.DeepEqual(fp.Anchors, Anchors(tt.key)) {
+			t.Errorf("%s: anchors = %q, want all of %q", tt.key, fp.Anchors, Anchors(tt.key))
+		}
+		if len(fp.Shape) == 0 || len(fp.Headings) == 0 {
+			t.Errorf("%s: empty fingerprint %+v", tt.key, fp)
+		}
+	}
+}
+
+func TestCompareLayout(t *testing.T) {
+	page := readPage(t, "testdata/nook/page_2.html")
+	known := Fingerprint("acp", page)
+
+	if d := CompareLayout("acp", known, Fingerprint("acp", page), DefaultLayoutTolerance); d != nil {
+		t.Errorf("unchanged page drifted: %s", d)
+	}
+
+	renamed := strings.Replace(page, "AUDIENCE ANALYSIS", "AUDIENCE INSIGHTS", 1)
+	d := CompareLayout("acp", known, Fingerprint("acp", renamed), DefaultLayoutTolerance)
+	if d == nil {
+		t.Fatal("renamed section heading was not reported")
+	}
+	if !reflect.DeepEqual(d.Missing, []string{"AUDIENCE ANALYSIS"}) || d.Renamed["AUDIENCE ANALYSIS"] != "AUDIENCE INSIGHTS" {
+		t.Errorf("drift = %+v", d)
+	}
+	if want := `missing anchors: "AUDIENCE ANALYSIS" (renamed to "AUDIENCE INSIGHTS"?)`; !strings.Contains(d.String(), want) {
+		t.Errorf("String() = %q, want it to contain %q", d.String(), want)
+	}
+
+	// The payload corpus renders the ladder without the section markup
+	redesigned := Fingerprint("value-ladder", readPage(t, "testdata/payload/page_5.html"))
+	d = CompareLayout("value-ladder", Fingerprint("value-ladder", readPage(t, "testdata/nook/page_5.html")), redesigned, DefaultLayoutTolerance)
+	if d == nil || d.Similarity >= 1-DefaultLayoutTolerance || len(d.Missing) == 0 {
+		t.Errorf("redesigned page: drift = %+v", d)
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	got := replacements(
+		[]string{"Intro", "AUDIENCE ANALYSIS", "COMMUNITY ANALYSIS", "Outro"},
+		[]string{"Intro", "AUDIENCE INSIGHTS", "COMMUNITY ANALYSIS", "New", "Outro"},
+	)
+	want := map[string]string{"AUDIENCE ANALYSIS": "AUDIENCE INSIGHTS"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replacements = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// trackingParams are query parameters that only identify the click, not the
+// page. Parameters starting with "utm_" are dropped as well.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"mc_cid": true, "mc_eid": true, "igshid": true, "ref_src": true, "ref_url": true,
+	"_hsenc": true, "_hsmi": true, "mkt_tok": true, "twclid": true, "li_fat_id": true,
+}
+
+// Links returns the outbound links of a page fetched from site in page
+// order: every <a> whose href leads off the site, resolved to an absolute
+// URL without tracking parameters. A URL linked more than once is listed
+// once, with the first non-empty anchor text.
+func Links(site *url.URL, page string) []model.Link {
+	doc := parseDocument(page)
+	return outboundLinks(site, doc, doc.all())
+}
+
+// outboundLinks returns the outbound links in s, as Links does.
+func outboundLinks(site *url.URL, doc *document, s span) []model.Link {
+	var links []model.Link
+	seen := make(map[string]int)
+	doc.each(s, isTag("a"), func(a *html.Node) {
+		u, ok := outboundURL(site, attr(a, "href"))
+		if !ok {
+			return
+		}
+		text := nodeText(a)
+		if i, dup := seen[u]; dup {
+			if links[i].Text == "" {
+				links[i].Text = text
+			}
+			return
+		}
+		seen[u] = len(links)
+		links = append(links, model.Link{Text: text, URL: u})
+	})
+	return links
+}
+
+// outboundURL resolves href against site and reports whether it leads to
+// another web site, returning it without tracking parameters. With a nil
+// site only absolute links are outbound.
+func outboundURL(site *url.URL, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	u := ref
+	if site != nil {
+		u = site.ResolveReference(ref)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || isSiteHost(site, u.Hostname()) {
+		return "", false
+	}
+	u.RawQuery = stripTracking(u.RawQuery)
+	return u.String(), true
+}
+
+// isSiteHost reports whether host is site or one of its subdomains.
+func isSiteHost(site *url.URL, host string) bool {
+	if site == nil {
+		return false
+	}
+	siteHost := strings.ToLower(strings.TrimPrefix(site.Hostname(), "www."))
+	host = strings.ToLower(host)
+	return host == siteHost || strings.HasSuffix(host, "."+siteHost)
+}
+
+// stripTracking drops tracking parameters from a raw query, keeping the
+// order of the others.
+func stripTracking(query string) string {
+	if query == "" {
+		return ""
+	}
+	var kept []string
+	for _, param := range strings.Split(query, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(name); err == nil {
+			name = strings.ToLower(name)
+			if strings.HasPrefix(name, "utm_") || trackingParams[name] {
+				continue
+			}
+		}
+		if param != "" {
+			kept = append(kept, param)
+		}
+	}
+	return strings.Join(kept, "&")
+}
@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestOutboundURL(t *testing.T) {
+	tests := []struct {
+		href string
+		want string // "" when the link is not outbound
+	}{
+		{"https://www.reddit.com/r/WorkFromHome/comments/abc/", "https://www.reddit.com/r/WorkFromHome/comments/abc/"},
+		{"https://trends.google.com/trends/explore?q=reading+nook&utm_source=ib&geo=US", "https://trends.google.com/trends/explore?q=reading+nook&geo=US"},
+		{"https://example.com/a?fbclid=x&UTM_Campaign=y", "https://example.com/a"},
+		{"https://example.com/a?gclid=1#section", "https://example.com/a#section"},
+		{"//news.example.org/story", "https://news.example.org/story"},
+		{"/idea/nook/why-now", ""},
+		{"https://www.ideabrowser.com/pricing", ""},
+		{"https://app.ideabrowser.com/", ""},
+		{"#top", ""},
+		{"mailto:hello@example.com", ""},
+		{"javascript:void(0)", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, ok := outboundURL(testSite, tt.href)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("outboundURL(%q) = %q, %v; want %q", tt.href, got, ok, tt.want)
+		}
+	}
+}
+
+// TestOutboundURLSite checks links back to another site, such as a test
+// server, are not outbound when the pages come from it.
+func TestOutboundURLSite(t *testing.T) {
+	staging, _ := url.Parse("http://127.0.0.1:8080")
+	for _, tt := range []struct {
+		site *url.URL
+		href string
+		want string
+	}{
+		{staging, "http://127.0.0.1:8080/idea/nook", ""},
+		{staging, "/idea/nook", ""},
+		{staging, "https://www.reddit.com/r/books/", "https://www.reddit.com/r/books/"},
+		{testSite, "http://127.0.0.1:8080/idea/nook", "http://127.0.0.1:8080/idea/nook"},
+		{nil, "/idea/nook", ""},
+		{nil, "https://www.reddit.com/r/books/", "https://www.reddit.com/r/books/"},
+	} {
+		got, ok := outboundURL(tt.site, tt.href)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("outboundURL(%v, %q) = %q, %v; want %q", tt.site, tt.href, got, ok, tt.want)
+		}
+	}
+}
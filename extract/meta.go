@@ -0,0 +1,246 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// Sources of idea title, description and date candidates.
+const (
+	SourceJSONLD    = "json-ld"
+	SourceOpenGraph = "og"
+	SourceMeta      = "meta"
+	SourceHeading   = "h1"
+	SourceTitle     = "title"
+	SourceLead      = "lead"
+	SourceTime      = "time"
+	SourceText      = "text"
+)
+
+// sourceWeights rank candidate sources: structured data the site publishes
+// for crawlers beats visible markup, which beats the document title and
+// free text.
+var sourceWeights = map[string]float64{
+	SourceJSONLD:    4,
+	SourceOpenGraph: 3,
+	SourceMeta:      2,
+	SourceHeading:   3,
+	SourceTitle:     2,
+	SourceLead:      2,
+	SourceTime:      2,
+	SourceText:      1,
+}
+
+// siteName is stripped from document and OpenGraph titles.
+const siteName = "ideabrowser"
+
+var (
+	ldJSONSel     = cascadia.MustCompile(`script[type="application/ld+json"]`)
+	timeSel       = cascadia.MustCompile(`time[datetime]`)
+	notIdeaLDType = map[string]bool{
+		"WebSite": true, "WebPage": true, "Organization": true, "BreadcrumbList": true,
+		"Person": true, "ImageObject": true, "SearchAction": true,
+	}
+)
+
+// Field is an extracted value with the source it came from and how well the
+// candidates agreed on it.
+type Field struct {
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"`
+	// Confidence is the share of candidate weight agreeing with Value, from
+	// 0 (no candidates) to 1 (every candidate agrees).
+	Confidence float64 `json:"confidence"`
+}
+
+// PageMeta is the headline information of an idea page.
+type PageMeta struct {
+	Title       Field
+	Description Field
+	Date        Field
+	// Warnings describe fields whose candidates disagreed.
+	Warnings []string
+}
+
+type candidate struct {
+	value  string
+	source string
+}
+
+// Meta extracts the idea's title, description and publication date from a
+// page. Each is chosen among candidates from JSON-LD, OpenGraph and meta
+// tags, the first <h1> and the paragraph after it, and the <title>.
+// Candidates that agree pool their weight; the best-supported value wins.
+func Meta(page string) *PageMeta {
+	doc := parseDocument(page)
+	var titles, descriptions, dates []candidate
+	add := func(list *[]candidate, source, value string) {
+		if value = strings.Join(strings.Fields(value), " "); value != "" {
+			*list = append(*list, candidate{value, source})
+		}
+	}
+
+	// JSON-LD describing the idea itself
+	for _, n := range doc.selectIn(doc.all(), ldJSONSel) {
+		if obj := ldObject(n); obj != nil {
+			p := &payload{}
+			add(&titles, SourceJSONLD, p.str(field(obj, "headline", "name")))
+			add(&descriptions, SourceJSONLD, p.str(field(obj, "description")))
+			add(&dates, SourceJSONLD, displayDate(p.str(field(obj, "datePublished", "dateCreated"))))
+			break
+		}
+	}
+
+	// OpenGraph and plain meta tags
+	doc.each(doc.all(), isTag("meta"), func(n *html.Node) {
+		name := attr(n, "property")
+		if name == "" {
+			name = attr(n, "name")
+		}
+		content := attr(n, "content")
+		switch name {
+		case "og:title":
+			add(&titles, SourceOpenGraph, withoutSiteName(content))
+		case "og:description":
+			add(&descriptions, SourceOpenGraph, content)
+		case "description":
+			add(&descriptions, SourceMeta, content)
+		case "article:published_time":
+			add(&dates, SourceMeta, displayDate(content))
+		}
+	})
+
+	// The first heading, and the lead paragraph following it
+	if h1 := doc.first(doc.all(), func(n *html.Node) bool { return n.Data == "h1" && nodeText(n) != "" }); h1 != nil {
+		add(&titles, SourceHeading, nodeText(h1))
+		lead := doc.first(doc.after(h1, doc.all()), func(n *html.Node) bool { return n.Data == "p" && nodeText(n) != "" })
+		if lead != nil {
+			add(&descriptions, SourceLead, nodeText(lead))
+		}
+	}
+
+	if title := doc.first(doc.all(), isTag("title")); title != nil {
+		add(&titles, SourceTitle, withoutSiteName(nodeText(title)))
+	}
+
+	if t := doc.firstIn(doc.all(), timeSel); t != nil {
+		add(&dates, SourceTime, displayDate(attr(t, "datetime")))
+	}
+	add(&dates, SourceText, dateRe.FindString(doc.text()))
+
+	meta := &PageMeta{}
+	var warning string
+	if meta.Title, warning = rank("title", titles, overlaps); warning != "" {
+		meta.Warnings = append(meta.Warnings, warning)
+	}
+	if meta.Description, warning = rank("description", descriptions, overlaps); warning != "" {
+		meta.Warnings = append(meta.Warnings, warning)
+	}
+	if meta.Date, warning = rank("date", dates, strings.EqualFold); warning != "" {
+		meta.Warnings = append(meta.Warnings, warning)
+	}
+	return meta
+}
+
+// ldObject returns the first JSON-LD object in script n that describes
+// content rather than the site, or nil.
+func ldObject(n *html.Node) map[string]interface{} {
+	if n.FirstChild == nil {
+		return nil
+	}
+	var v interface{}
+	if json.Unmarshal([]byte(n.FirstChild.Data), &v) != nil {
+		return nil
+	}
+	p := &payload{roots: []interface{}{v}}
+	return p.object(func(m map[string]interface{}) bool {
+		kind, _ := m["@type"].(string)
+		return kind != "" && !notIdeaLDType[kind] && p.str(field(m, "headline", "name")) != ""
+	})
+}
+
+// withoutSiteName drops the site name segment from a title such as
+// "Idea | IdeaBrowser". A title that is only the site name yields "".
+func withoutSiteName(title string) string {
+	var kept []string
+	for _, part := range strings.Split(title, "|") {
+		if part = strings.TrimSpace(part); part != "" && !strings.Contains(strings.ToLower(part), siteName) {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, " | ")
+}
+
+// overlaps reports whether two texts agree: equal once case, punctuation
+// and spacing are ignored, or one a truncation of the other.
+func overlaps(a, b string) bool {
+	a, b = normalizeKey(a), normalizeKey(b)
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// rank groups agreeing candidates, summing their source weights, and picks
+// the heaviest group, preferring the earlier group on a tie. The chosen
+// value is the group's strongest candidate. It returns a warning when the
+// candidates fall into more than one group.
+func rank(name string, candidates []candidate, agree func(a, b string) bool) (Field, string) {
+	type group struct {
+		best   candidate
+		weight float64
+	}
+	var groups []*group
+	total := 0.0
+	for _, c := range candidates {
+		w := sourceWeights[c.source]
+		total += w
+
+		var g *group
+		for _, existing := range groups {
+			if agree(existing.best.value, c.value) {
+				g = existing
+				break
+			}
+		}
+		if g == nil {
+			g = &group{best: c}
+			groups = append(groups, g)
+		} else if w > sourceWeights[g.best.source] {
+			g.best = c
+		}
+		g.weight += w
+	}
+	if len(groups) == 0 {
+		return Field{}, ""
+	}
+
+	chosen := groups[0]
+	for _, g := range groups[1:] {
+		if g.weight > chosen.weight {
+			chosen = g
+		}
+	}
+	result := Field{
+		Value:      chosen.best.value,
+		Source:     chosen.best.source,
+		Confidence: math.Round(chosen.weight/total*100) / 100,
+	}
+	if len(groups) == 1 {
+		return result, ""
+	}
+
+	var others []string
+	for _, g := range groups {
+		if g != chosen {
+			others = append(others, fmt.Sprintf("%s %q", g.best.source, g.best.value))
+		}
+	}
+	return result, fmt.Sprintf("%s candidates disagree: using %s %q over %s",
+		name, result.Source, result.Value, strings.Join(others, ", "))
+}
@@ -0,0 +1,183 @@
+package extract
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// detailPage is the typed content of a detail page, filled block by block.
+type detailPage interface {
+	Add(model.Block)
+}
+
+// fill adds blocks to page and reports whether there were any.
+func fill(page detailPage, blocks []model.Block) bool {
+	for _, b := range blocks {
+		page.Add(b)
+	}
+	return len(blocks) > 0
+}
+
+// pageBlocks splits a detail page fetched from site into its headed blocks,
+// in page order: each <h3> with the text of the elements following it up to
+// the next heading, and each font-medium label with the value element next
+// to it.
+func pageBlocks(site *url.URL, page string) []model.Block {
+	doc := parseDocument(page)
+	var out []model.Block
+	doc.each(doc.all(), func(n *html.Node) bool {
+		return n.Data == "h3" || fontMediumSel.Match(n)
+	}, func(n *html.Node) {
+		heading, ok := plainText(n)
+		if !ok || heading == "" || len(heading) >= 50 {
+			return
+		}
+
+		var content []*html.Node
+		label := n.Data != "h3"
+		if label {
+			if value := adjacentElement(n); value != nil {
+				content = append(content, value)
+			}
+		} else {
+			for sib := nextElementSibling(n); sib != nil && !isHeading(sib); sib = nextElementSibling(sib) {
+				content = append(content, sib)
+			}
+		}
+
+		var paragraphs []string
+		var links []model.Link
+		for _, c := range content {
+			// List items each make a paragraph
+			items := []*html.Node{c}
+			if c.Data == "ul" || c.Data == "ol" {
+				items = nil
+				for li := c.FirstChild; li != nil; li = li.NextSibling {
+					if li.Data == "li" {
+						items = append(items, li)
+					}
+				}
+			}
+			for _, item := range items {
+				if text := inlineText(item); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			}
+			links = append(links, outboundLinks(site, doc, span{doc.index[c], doc.end(c)})...)
+		}
+		if len(paragraphs) == 0 {
+			return
+		}
+		out = append(out, model.Block{
+			Heading: heading,
+			Text:    strings.Join(paragraphs, "\n\n"),
+			Label:   label,
+			Links:   links,
+		})
+	})
+	return out
+}
+
+func isHeading(n *html.Node) bool {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4":
+		return true
+	}
+	return false
+}
+
+// inlineElements are the elements that flow within a line of text.
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "code": true, "em": true, "i": true,
+	"mark": true, "small": true, "span": true, "strong": true, "sub": true,
+	"sup": true, "u": true,
+}
+
+// inlineText is like nodeText but keeps inline elements and comments
+// within their line, so "part time (<a>source</a>)" reads
+// "part time (source)" and "10-20 hours<!-- --> per week" keeps its spacing.
+func inlineText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+		case html.ElementNode:
+			if skipElement(n) {
+				return
+			}
+			inline := inlineElements[n.Data]
+			if !inline {
+				b.WriteByte(' ')
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+			if !inline {
+				b.WriteByte(' ')
+			}
+		case html.CommentNode:
+		default:
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// blocks returns the object stored under one of keys as page blocks: each
+// text field under its humanized key, and each list of named objects as
+// one block per item. Keys are taken in sorted order, as the payload keeps
+// none. It returns nil when the payload has no such object.
+func (p *payload) blocks(keys ...string) []model.Block {
+	var out []model.Block
+	p.collect(p.asObject(p.lookup(keys...)), &out, 0)
+	return out
+}
+
+func (p *payload) collect(m map[string]interface{}, out *[]model.Block, depth int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !skippedKeys[normalizeKey(k)] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := p.resolve(m[k])
+		if nested := p.asObject(v); nested != nil {
+			if depth < 3 {
+				p.collect(nested, out, depth+1)
+			}
+			continue
+		}
+		if items, ok := v.([]interface{}); ok && len(items) > 0 && p.asObject(items[0]) != nil {
+			for _, item := range items {
+				obj := p.asObject(item)
+				heading := p.str(field(obj, "name", "title", "heading", "source", "trend", "phase"))
+				text := p.str(field(obj, "description", "evidence", "details", "text", "summary"))
+				if heading == "" || text == "" {
+					continue
+				}
+				b := model.Block{Heading: heading, Text: text}
+				if u, ok := outboundURL(p.site, p.str(field(obj, "url", "link", "href", "sourceUrl"))); ok {
+					b.Links = []model.Link{{URL: u}}
+				}
+				*out = append(*out, b)
+			}
+			continue
+		}
+		if value := p.str(v); value != "" {
+			*out = append(*out, model.Block{Heading: humanizeKey(k), Text: value})
+		}
+	}
+}
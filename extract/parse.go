@@ -0,0 +1,231 @@
+package extract
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+var (
+	slugHrefRe = regexp.MustCompile(`href="/idea/(` + model.SlugPattern + `)/`)
+	slugPathRe = regexp.MustCompile(`/idea/(` + model.SlugPattern + `)/`)
+	slugLinkRe = regexp.MustCompile(`href="/idea/(` + model.SlugPattern + `)[/"?#]`)
+)
+
+// IdeaSlug finds the slug of the idea linked from the idea-of-the-day page.
+func IdeaSlug(html string) string {
+	// Look for an href to a page of the idea and take its slug, which
+	// must match model.SlugPattern as the schema requires
+	if matches := slugHrefRe.FindStringSubmatch(html); len(matches) > 1 {
+		return matches[1]
+	}
+
+	// Try alternative pattern if first one doesn't match
+	if matches := slugPathRe.FindStringSubmatch(html); len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+// IdeaSlugs returns every distinct idea slug linked from a listing page, in
+// order of appearance.
+func IdeaSlugs(html string) []string {
+	var slugs []string
+	seen := make(map[string]bool)
+	for _, m := range slugLinkRe.FindAllStringSubmatch(html, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			slugs = append(slugs, m[1])
+		}
+	}
+	return slugs
+}
+
+// Parse builds the IdeaData for slug from the scraped pages, keyed by page
+// path relative to the idea ("acp", "value-equation", ...) with the main page
+// under "/idea-of-the-day". Each page is read from its embedded Next.js
+// payload when it has one, falling back to the rendered HTML otherwise.
+// Every page is classified into idea.PageAccess first; pages showing a wall
+// instead of their content are left out of the extraction. Links resolve
+// against site, the site the pages were fetched from, and links back to it
+// are not outbound.
+func Parse(site *url.URL, slug string, pages map[string]string) *model.IdeaData {
+	idea := &model.IdeaData{
+		SchemaVersion: model.SchemaVersion,
+		Slug:          slug,
+	}
+	if len(pages) > 0 {
+		idea.PageAccess = Classify(pages)
+		pages = Accessible(pages, idea.PageAccess)
+	}
+
+	payloads := make(map[string]*payload)
+	for key, page := range pages {
+		if p := decodePayload(page); p != nil {
+			p.site = site
+			payloads[key] = p
+		}
+	}
+
+	// Parse main page
+	if mainPage, ok := pages["/idea-of-the-day"]; ok {
+		var found bool
+		if p := payloads["/idea-of-the-day"]; p != nil {
+			idea.Title, idea.Description, idea.Date, idea.Tags, found = p.ideaInfo(slug)
+		}
+		if !found {
+			meta := Meta(mainPage)
+			idea.Title, idea.Description, idea.Date = meta.Title.Value, meta.Description.Value, meta.Date.Value
+			idea.Confidence = map[string]float64{
+				"title":       meta.Title.Confidence,
+				"description": meta.Description.Confidence,
+				"date":        meta.Date.Confidence,
+			}
+			idea.Warnings = append(idea.Warnings, meta.Warnings...)
+			idea.Tags = Tags(mainPage)
+		}
+	}
+
+	// Initialize Framework Fit data
+	idea.FrameworkFit = &model.FrameworkData{}
+
+	// Parse framework pages
+	if valueEqPage, ok := pages["value-equation"]; ok {
+		if p := payloads["value-equation"]; p == nil || !p.valueEquation(idea.FrameworkFit) {
+			tempFramework := Framework(valueEqPage)
+			if tempFramework != nil {
+				idea.FrameworkFit.ValueEquation = tempFramework.ValueEquation
+			}
+		}
+	}
+
+	if matrixPage, ok := pages["value-matrix"]; ok {
+		if p := payloads["value-matrix"]; p == nil || !p.marketMatrix(idea.FrameworkFit) {
+			tempFramework := Framework(matrixPage)
+			if tempFramework != nil {
+				idea.FrameworkFit.MarketMatrix = tempFramework.MarketMatrix
+			}
+		}
+	}
+
+	if acpPage, ok := pages["acp"]; ok {
+		// Extract both ACP detailed data and framework scores
+		p := payloads["acp"]
+		if p != nil {
+			idea.ACP = p.acp()
+		}
+		if idea.ACP == nil {
+			idea.ACP = ACP(acpPage)
+		}
+		if p == nil || !p.acpScores(idea.FrameworkFit) {
+			tempFramework := Framework(acpPage)
+			if tempFramework != nil {
+				idea.FrameworkFit.ACPFramework = tempFramework.ACPFramework
+			}
+		}
+	}
+
+	if ladderPage, ok := pages["value-ladder"]; ok {
+		if p := payloads["value-ladder"]; p == nil || !p.valueLadder(idea.FrameworkFit) {
+			tempFramework := Framework(ladderPage)
+			if tempFramework != nil && len(tempFramework.ValueLadderStages) > 0 {
+				idea.FrameworkFit.ValueLadderStages = tempFramework.ValueLadderStages
+				idea.FrameworkFit.ValueLadderSummary = tempFramework.ValueLadderSummary
+			}
+		}
+		// Also store as separate page data
+		idea.ValueLadder = PageData(ladderPage)
+	}
+
+	// Parse the detail pages, looking each up in the payload under its own
+	// key
+	for pageName, set := range pageFields(idea) {
+		pageHTML, ok := pages[pageName]
+		if !ok {
+			continue
+		}
+		var blocks []model.Block
+		if p := payloads[pageName]; p != nil {
+			blocks = p.blocks(path.Base(pageName))
+		}
+		if len(blocks) == 0 {
+			blocks = pageBlocks(site, pageHTML)
+		}
+		if len(blocks) > 0 {
+			set(onPage(blocks, pageName))
+		}
+	}
+
+	idea.Metrics = model.CollectMetrics(idea)
+
+	// Outbound links of every page, in page key order
+	keys := make([]string, 0, len(pages))
+	for key := range pages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, link := range Links(site, pages[key]) {
+			link.Page = key
+			idea.Links = append(idea.Links, link)
+		}
+	}
+
+	return idea
+}
+
+// onPage records pageName as the page of every link in blocks.
+func onPage(blocks []model.Block, pageName string) []model.Block {
+	for _, b := range blocks {
+		for i := range b.Links {
+			b.Links[i].Page = pageName
+		}
+	}
+	return blocks
+}
+
+// pageFields returns, by page key, the setters filling the detail pages of
+// idea from their blocks.
+func pageFields(idea *model.IdeaData) map[string]func([]model.Block) {
+	return map[string]func([]model.Block){
+		"build/landing-page": func(b []model.Block) { idea.BuildInfo = &model.BuildInfo{}; fill(idea.BuildInfo, b) },
+		"founder-fit":        func(b []model.Block) { idea.FounderFit = &model.FounderFit{}; fill(idea.FounderFit, b) },
+		"why-now":            func(b []model.Block) { idea.WhyNow = &model.WhyNow{}; fill(idea.WhyNow, b) },
+		"proof-signals":      func(b []model.Block) { idea.ProofSignals = &model.ProofSignals{}; fill(idea.ProofSignals, b) },
+		"market-gap":         func(b []model.Block) { idea.MarketGap = &model.MarketGap{}; fill(idea.MarketGap, b) },
+		"execution-plan":     func(b []model.Block) { idea.ExecutionPlan = &model.ExecutionPlan{}; fill(idea.ExecutionPlan, b) },
+	}
+}
+
+// Record maps an idea record fetched from the IdeaBrowser backend onto
+// IdeaData. Fields are read the same way as from embedded page payloads, so
+// column names may be camelCase or snake_case. ok is false when record is
+// not an idea with slug and a title. Links resolve against site.
+func Record(site *url.URL, slug string, record map[string]interface{}) (*model.IdeaData, bool) {
+	p := &payload{roots: []interface{}{record}, rows: make(map[string]interface{}), site: site}
+
+	idea := &model.IdeaData{SchemaVersion: model.SchemaVersion, Slug: slug}
+	var ok bool
+	idea.Title, idea.Description, idea.Date, idea.Tags, ok = p.ideaInfo(slug)
+	if !ok {
+		return nil, false
+	}
+
+	idea.FrameworkFit = &model.FrameworkData{}
+	p.valueEquation(idea.FrameworkFit)
+	p.marketMatrix(idea.FrameworkFit)
+	p.acpScores(idea.FrameworkFit)
+	p.valueLadder(idea.FrameworkFit)
+	idea.ACP = p.acp()
+
+	for pageName, set := range pageFields(idea) {
+		if blocks := p.blocks(path.Base(pageName)); len(blocks) > 0 {
+			set(onPage(blocks, pageName))
+		}
+	}
+	idea.Metrics = model.CollectMetrics(idea)
+	return idea, true
+}
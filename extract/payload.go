@@ -0,0 +1,601 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/html"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// payload is the structured data a server-rendered Next.js page embeds for
+// hydration: the __NEXT_DATA__ JSON of the pages router, and the React
+// Server Components rows streamed through self.__next_f.push by the app
+// router. Field lookups search every embedded value breadth-first, so they
+// work whichever router and component tree the page uses.
+type payload struct {
+	roots []interface{}
+	rows  map[string]interface{}
+	// site is the site the payload came from, against which its links
+	// resolve.
+	site *url.URL
+}
+
+const flightPush = "self.__next_f.push("
+
+// decodePayload returns the page's embedded payload, or nil when the page
+// has none.
+func decodePayload(page string) *payload {
+	if !strings.Contains(page, "__NEXT_DATA__") && !strings.Contains(page, flightPush) {
+		return nil
+	}
+
+	p := &payload{rows: make(map[string]interface{})}
+	var flight strings.Builder
+	doc := parseDocument(page)
+	doc.each(doc.all(), isTag("script"), func(n *html.Node) {
+		if n.FirstChild == nil {
+			return
+		}
+		src := n.FirstChild.Data
+
+		if attr(n, "id") == "__NEXT_DATA__" {
+			var v interface{}
+			if json.Unmarshal([]byte(src), &v) == nil {
+				p.roots = append(p.roots, v)
+			}
+			return
+		}
+
+		// Each push is [0] (bootstrap), [1, "chunk"], [2, "form state"] or
+		// [3, "base64 chunk"]; only type 1 carries flight rows as text
+		for i := strings.Index(src, flightPush); i >= 0; i = strings.Index(src, flightPush) {
+			src = src[i+len(flightPush):]
+			var chunk []interface{}
+			if json.NewDecoder(strings.NewReader(src)).Decode(&chunk) != nil || len(chunk) < 2 {
+				continue
+			}
+			if kind, _ := chunk[0].(float64); kind == 1 {
+				text, _ := chunk[1].(string)
+				flight.WriteString(text)
+			}
+		}
+	})
+
+	for _, id := range p.parseFlight(flight.String()) {
+		p.roots = append(p.roots, p.rows[id])
+	}
+	if len(p.roots) == 0 {
+		return nil
+	}
+	return p
+}
+
+// parseFlight decodes RSC flight rows ("<hex id>:<json>\n" or
+// "<hex id>:T<hex length>,<text>") into p.rows and returns the row ids in
+// stream order. Rows that are not JSON (module and hint rows carry a letter
+// tag before their JSON) are decoded past the tag or skipped.
+func (p *payload) parseFlight(data string) []string {
+	var order []string
+	for data != "" {
+		colon := strings.IndexByte(data, ':')
+		newline := strings.IndexByte(data, '\n')
+		if colon <= 0 || (newline >= 0 && newline < colon) || !isHex(data[:colon]) {
+			if newline < 0 {
+				break
+			}
+			data = data[newline+1:]
+			continue
+		}
+		id := data[:colon]
+		data = data[colon+1:]
+
+		// Text rows are length-prefixed and not newline terminated
+		if strings.HasPrefix(data, "T") {
+			if comma := strings.IndexByte(data, ','); comma > 1 {
+				// The length is untrusted: ParseUint refuses a sign and the
+				// comparison is made before converting to int
+				if n, err := strconv.ParseUint(data[1:comma], 16, 64); err == nil && n <= uint64(len(data)-comma-1) {
+					p.rows[id] = data[comma+1 : comma+1+int(n)]
+					order = append(order, id)
+					data = data[comma+1+int(n):]
+					continue
+				}
+			}
+		}
+
+		line := data
+		data = ""
+		if newline := strings.IndexByte(line, '\n'); newline >= 0 {
+			line, data = line[:newline], line[newline+1:]
+		}
+		line = strings.TrimLeftFunc(line, unicode.IsUpper)
+		var v interface{}
+		if json.Unmarshal([]byte(line), &v) == nil {
+			p.rows[id] = v
+			order = append(order, id)
+		}
+	}
+	return order
+}
+
+func isHex(s string) bool {
+	_, err := strconv.ParseUint(s, 16, 64)
+	return err == nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// resolve follows an RSC reference ("$1a", "$L1a", "$@1a") to the row it
+// names and unescapes "$$" literals. Other values are returned unchanged.
+func (p *payload) resolve(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "$") {
+		return v
+	}
+	if strings.HasPrefix(s, "$$") {
+		return s[1:]
+	}
+	if row, ok := p.rows[strings.TrimLeft(s[1:], "L@")]; ok {
+		return row
+	}
+	return v
+}
+
+// walk visits every map in the payload breadth-first, following references
+// to other rows once each, until visit returns true.
+func (p *payload) walk(visit func(map[string]interface{}) bool) {
+	queue := append([]interface{}(nil), p.roots...)
+	followed := make(map[string]bool)
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if visit(v) {
+				return
+			}
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				queue = append(queue, v[k])
+			}
+		case []interface{}:
+			queue = append(queue, v...)
+		case string:
+			if strings.HasPrefix(v, "$") && !strings.HasPrefix(v, "$$") && !followed[v] {
+				followed[v] = true
+				r := p.resolve(v)
+				if s, ok := r.(string); !ok || s != v {
+					queue = append(queue, r)
+				}
+			}
+		}
+	}
+}
+
+// object returns the first map in the payload that satisfies match.
+func (p *payload) object(match func(map[string]interface{}) bool) map[string]interface{} {
+	var found map[string]interface{}
+	p.walk(func(m map[string]interface{}) bool {
+		if match(m) {
+			found = m
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+// lookup returns the first object or array stored under one of keys
+// anywhere in the payload. Keys match after normalization, so "whyNow",
+// "why_now" and "Why Now" are the same key.
+func (p *payload) lookup(keys ...string) interface{} {
+	var found interface{}
+	p.walk(func(m map[string]interface{}) bool {
+		v := p.resolve(field(m, keys...))
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			found = v
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+// normalizeKey lowercases k and drops everything but letters and digits.
+func normalizeKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, k)
+}
+
+// field returns the value of the first of keys present in m, comparing
+// normalized keys.
+func field(m map[string]interface{}, keys ...string) interface{} {
+	if m == nil {
+		return nil
+	}
+	for _, key := range keys {
+		want := normalizeKey(key)
+		for k, v := range m {
+			if normalizeKey(k) == want {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// asObject returns v as an object, or nil.
+func (p *payload) asObject(v interface{}) map[string]interface{} {
+	m, _ := p.resolve(v).(map[string]interface{})
+	return m
+}
+
+// str returns v as trimmed text: strings as is, numbers formatted, and
+// arrays of strings joined with ", ".
+func (p *payload) str(v interface{}) string {
+	switch v := p.resolve(v).(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		var parts []string
+		for _, item := range v {
+			s, ok := p.resolve(item).(string)
+			if !ok {
+				return ""
+			}
+			if s = strings.TrimSpace(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// num returns v as an integer score: numbers are truncated and strings
+// such as "8" or "8/10" yield their leading number.
+func (p *payload) num(v interface{}) (int, bool) {
+	switch v := p.resolve(v).(type) {
+	case float64:
+		return int(v), true
+	case string:
+		v = strings.TrimSpace(v)
+		end := strings.IndexFunc(v, func(r rune) bool { return !unicode.IsDigit(r) })
+		if end < 0 {
+			end = len(v)
+		}
+		n, err := strconv.Atoi(v[:end])
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// score returns the score held by v: v itself when numeric, else its
+// score field.
+func (p *payload) score(v interface{}) (int, bool) {
+	if n, ok := p.num(v); ok {
+		return n, true
+	}
+	return p.num(field(p.asObject(v), "score", "value", "rating"))
+}
+
+// labels returns v as a list of labels: strings as is, objects by their
+// name, label or title.
+func (p *payload) labels(v interface{}) []string {
+	items, _ := p.resolve(v).([]interface{})
+	var out []string
+	for _, item := range items {
+		s := p.str(item)
+		if m := p.asObject(item); m != nil {
+			s = p.str(field(m, "name", "label", "title"))
+		}
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// byName returns a list of objects as one object keyed by each item's name,
+// label or title, or nil when v is not a list.
+func (p *payload) byName(v interface{}) map[string]interface{} {
+	items, ok := p.resolve(v).([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make(map[string]interface{})
+	for _, item := range items {
+		if m := p.asObject(item); m != nil {
+			if name := p.str(field(m, "name", "label", "title")); name != "" {
+				out[name] = item
+			}
+		}
+	}
+	return out
+}
+
+// payloadDateLayouts are the date formats accepted from payload fields,
+// which are rewritten to the "Jan 2, 2006" form shown on the page.
+var payloadDateLayouts = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+func displayDate(s string) string {
+	for _, layout := range payloadDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("Jan 2, 2006")
+		}
+	}
+	return s
+}
+
+// humanizeKey turns a payload key such as "marketTiming" or "market_timing"
+// into the heading form "Market Timing" used by the rendered pages.
+func humanizeKey(k string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(k)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+		}
+		if len(word) == 0 {
+			r = unicode.ToUpper(r)
+		}
+		word = append(word, r)
+	}
+	flush()
+	return strings.Join(words, " ")
+}
+
+// ideaInfo returns the title, description, date and tags of the idea object
+// for slug. ok is false when the payload holds no such object.
+func (p *payload) ideaInfo(slug string) (title, description, date string, tags []string, ok bool) {
+	idea := p.object(func(m map[string]interface{}) bool {
+		return p.str(field(m, "slug")) == slug && p.str(field(m, "title", "name")) != ""
+	})
+	if idea == nil {
+		return "", "", "", nil, false
+	}
+
+	title = p.str(field(idea, "title", "name"))
+	description = p.str(field(idea, "description", "summary", "tagline"))
+	if d := p.str(field(idea, "date", "publishedAt", "publishDate", "featuredDate", "ideaDate", "createdAt")); d != "" {
+		date = displayDate(d)
+	}
+	tags = p.labels(field(idea, "tags", "categories", "labels"))
+	return title, description, date, tags, true
+}
+
+// valueEquation fills the value equation from the payload and reports
+// whether the payload had one.
+func (p *payload) valueEquation(framework *model.FrameworkData) bool {
+	ve := p.asObject(p.lookup("valueEquation"))
+	if ve == nil {
+		return false
+	}
+	score, found := p.num(field(ve, "score", "overallScore", "overallRating", "overall"))
+
+	// Components sit on the value equation itself or under "components",
+	// keyed by name or as a list of named objects
+	parts := p.asObject(field(ve, "components", "scores"))
+	if parts == nil {
+		parts = p.byName(field(ve, "components", "scores"))
+	}
+	var components []model.ValueComponent
+	for _, c := range valueComponents {
+		keys := append([]string{c.name}, c.keys...)
+		v := field(ve, keys...)
+		if v == nil {
+			v = field(parts, keys...)
+		}
+		n, ok := p.score(v)
+		if !ok {
+			continue
+		}
+		obj := p.asObject(v)
+		maxScore, ok := p.num(field(obj, "maxScore", "max", "outOf", "scale"))
+		if !ok {
+			maxScore = 10
+		}
+		components = append(components, model.ValueComponent{
+			Name:        c.name,
+			Score:       n,
+			MaxScore:    maxScore,
+			Description: p.str(field(obj, "description", "explanation", "analysis")),
+		})
+	}
+	if !found && len(components) == 0 {
+		return false
+	}
+
+	framework.ValueEquation.Score = score
+	framework.ValueEquation.Rating = rating(score)
+	framework.ValueEquation.Description = p.str(field(ve, "analysis", "summary"))
+	framework.ValueEquation.Components = components
+	return true
+}
+
+// marketMatrix fills the market matrix from the payload and reports whether
+// the payload had one.
+func (p *payload) marketMatrix(framework *model.FrameworkData) bool {
+	mm := p.asObject(p.lookup("marketMatrix", "valueMatrix"))
+	if mm == nil {
+		return false
+	}
+
+	framework.MarketMatrix.Position = p.str(field(mm, "position", "quadrant"))
+	if n, ok := p.score(field(mm, "uniqueness", "uniquenessScore")); ok {
+		framework.MarketMatrix.Uniqueness = fmt.Sprintf("%d/10", n)
+	}
+	if n, ok := p.score(field(mm, "value", "valueScore")); ok {
+		framework.MarketMatrix.Value = fmt.Sprintf("%d/10", n)
+	}
+
+	description := p.str(field(mm, "description", "analysis", "summary"))
+	if description != "" && framework.MarketMatrix.Position != "" {
+		if analysis := p.str(field(mm, "positionAnalysis", "positionExplanation")); analysis != "" {
+			description += "\n\nPosition Analysis: " + analysis
+		}
+	}
+	if description != "" {
+		guide := p.asObject(field(mm, "quadrants"))
+		for _, name := range quadrants {
+			detail := p.str(field(guide, name))
+			if detail == "" {
+				detail = p.str(field(p.asObject(field(guide, name)), "description"))
+			}
+			if detail != "" {
+				description += "\n\n" + name + ": " + detail
+			}
+		}
+	}
+	framework.MarketMatrix.Description = description
+
+	return framework.MarketMatrix.Position != "" || description != ""
+}
+
+// acpScores fills the ACP framework scores from the payload and reports
+// whether the payload had any.
+func (p *payload) acpScores(framework *model.FrameworkData) bool {
+	scores := p.asObject(p.lookup("acpFramework", "acpScores", "acp"))
+	found := false
+	for _, s := range []struct {
+		name  string
+		score *int
+	}{
+		{"Audience", &framework.ACPFramework.Audience},
+		{"Community", &framework.ACPFramework.Community},
+		{"Product", &framework.ACPFramework.Product},
+	} {
+		if n, ok := p.score(field(scores, s.name+"Score", s.name)); ok {
+			*s.score = n
+			found = true
+		}
+	}
+
+	// Calculate overall ACP score
+	if framework.ACPFramework.Audience > 0 && framework.ACPFramework.Community > 0 && framework.ACPFramework.Product > 0 {
+		framework.ACPFramework.Overall = (framework.ACPFramework.Audience + framework.ACPFramework.Community + framework.ACPFramework.Product) / 3
+	}
+	return found
+}
+
+// acp returns the ACP analysis from the payload, or nil when it has none.
+func (p *payload) acp() *model.ACPData {
+	root := p.asObject(p.lookup("acpAnalysis", "acp"))
+	acp := &model.ACPData{}
+	found := false
+	for _, section := range acpSections(acp) {
+		obj := p.asObject(field(root, section.payloadKeys...))
+		if obj == nil {
+			continue
+		}
+		found = true
+		for _, f := range section.fields {
+			*f.dst = p.str(field(obj, f.label))
+		}
+	}
+	if !found {
+		return nil
+	}
+	return acp
+}
+
+// valueLadder fills the value ladder stages from the payload and reports
+// whether the payload had any. The ladder may be a list of stage objects
+// naming their stage, or an object keyed by stage.
+func (p *payload) valueLadder(framework *model.FrameworkData) bool {
+	ladder := p.lookup("valueLadder")
+	if m := p.asObject(ladder); m != nil {
+		if list := field(m, "stages", "offers"); list != nil {
+			ladder = p.resolve(list)
+		}
+	}
+
+	byStage := make(map[string]map[string]interface{})
+	switch l := ladder.(type) {
+	case []interface{}:
+		for _, item := range l {
+			obj := p.asObject(item)
+			name := normalizeKey(p.str(field(obj, "stage", "name", "type")))
+			if name == "" {
+				continue
+			}
+			for _, s := range ladderStages {
+				if strings.HasPrefix(normalizeKey(s.heading), name) && byStage[s.kind] == nil {
+					byStage[s.kind] = obj
+					break
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, s := range ladderStages {
+			if obj := p.asObject(field(l, s.heading, s.kind)); obj != nil {
+				byStage[s.kind] = obj
+			}
+		}
+	}
+
+	var stages model.LadderStages
+	for _, s := range ladderStages {
+		obj, ok := byStage[s.kind]
+		if !ok {
+			continue
+		}
+		stages = append(stages, model.LadderStage{
+			Kind:          s.kind,
+			Title:         p.str(field(obj, "title", "offer")),
+			Price:         model.ParsePrice(p.str(field(obj, "price", "pricing"))),
+			Description:   p.str(field(obj, "description")),
+			ValueProvided: p.str(field(obj, "Value Provided", "value")),
+			Goal:          p.str(field(obj, "goal")),
+		})
+	}
+	if len(stages) == 0 {
+		return false
+	}
+	framework.ValueLadderStages = stages
+	framework.ValueLadderSummary = model.SummarizeLadder(stages)
+	return true
+}
+
+// skippedKeys are bookkeeping fields left out of page blocks.
+var skippedKeys = map[string]bool{
+	"id": true, "slug": true, "ideaid": true, "typename": true,
+	"createdat": true, "updatedat": true,
+}
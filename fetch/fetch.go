@@ -0,0 +1,120 @@
+// Package fetch downloads IdeaBrowser pages with browser-like headers.
+package fetch
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	userAgent      = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/135.0.0.0 Safari/537.36 OPR/120.0.0.0"
+	acceptHTML     = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"
+	acceptLanguage = "en-GB,en-US;q=0.9,en;q=0.8,ru;q=0.7,ar;q=0.6,pl;q=0.5,de;q=0.4,fr;q=0.3,zh-CN;q=0.2,zh;q=0.1,th;q=0.1,vi;q=0.1"
+)
+
+// StatusError reports a page that answered with a non-200 status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	// RetryAfter is the wait requested by the server's Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusUnauthorized {
+		return fmt.Sprintf("unauthorized access to %s (token may be expired)", e.URL)
+	}
+	return fmt.Sprintf("failed to scrape %s: status %d", e.URL, e.StatusCode)
+}
+
+// Fetcher issues GET requests through an HTTP client whose cookie jar
+// carries the session.
+type Fetcher struct {
+	Client *http.Client
+}
+
+// Response is a fetched page: its decoded body, the final URL after
+// redirects, and the status and headers it was served with.
+type Response struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+// Get fetches url and returns the decoded body. Headers in extra are added
+// on top of the default browser headers.
+func (f *Fetcher) Get(ctx context.Context, url string, extra http.Header) (string, error) {
+	resp, err := f.Fetch(ctx, url, extra)
+	if err != nil {
+		return "", err
+	}
+	return resp.Body, nil
+}
+
+// Fetch is like Get but returns the whole response. A non-200 answer
+// returns both the response, body included, and a *StatusError.
+func (f *Fetcher) Fetch(ctx context.Context, url string, extra http.Header) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", acceptHTML)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	req.Header.Set("Accept-Language", acceptLanguage)
+	for k, vs := range extra {
+		for _, v := range vs {
+			req.Header.Set(k, v)
+		}
+	}
+
+	// The cookies in the client's jar will be automatically sent
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	page := &Response{
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+	if resp.StatusCode != http.StatusOK {
+		// The body of an error page is kept only when it could be read
+		if err != nil {
+			page.Body = ""
+		}
+		return page, &StatusError{
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// readBody reads the body of resp, decompressing gzip content.
+func readBody(resp *http.Response) (string, error) {
+	reader := io.Reader(resp.Body)
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	body, err := io.ReadAll(reader)
+	return string(body), err
+}
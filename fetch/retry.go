@@ -0,0 +1,123 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed page requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries per page, including the first.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles per retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between retries.
+	MaxDelay time.Duration
+	// MaxRetryAfter is the longest Retry-After the policy will honor. A
+	// server asking for a longer wait fails the page instead.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy is used when a client is given no policy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   4,
+	BaseDelay:     time.Second,
+	MaxDelay:      30 * time.Second,
+	MaxRetryAfter: 2 * time.Minute,
+}
+
+// Backoff returns the delay before retry number attempt (1 for the first
+// retry): exponential growth from BaseDelay capped at MaxDelay, with full
+// jitter.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d))) + 1
+}
+
+// PermanentError wraps a failure that retrying cannot fix, such as a record
+// that does not exist or a response that does not decode.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent marks err as not worth retrying. It returns nil for nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// Retryable reports whether err is worth retrying: transport failures,
+// timeouts, 429 and 5xx responses. Context cancellation and errors marked
+// with Permanent are not.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusTooManyRequests,
+			statusErr.StatusCode == http.StatusRequestTimeout,
+			statusErr.StatusCode >= 500:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// Delay returns how long to wait before retry number attempt after err,
+// preferring the server's Retry-After on 429 and 503. ok is false when the
+// server asks for a wait longer than MaxRetryAfter.
+func (p RetryPolicy) Delay(attempt int, err error) (time.Duration, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 &&
+		(statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode == http.StatusServiceUnavailable) {
+		if p.MaxRetryAfter > 0 && statusErr.RetryAfter > p.MaxRetryAfter {
+			return 0, false
+		}
+		return statusErr.RetryAfter, true
+	}
+	return p.Backoff(attempt), true
+}
+
+// ParseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date.
+func ParseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
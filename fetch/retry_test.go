@@ -0,0 +1,128 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, limit := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second, // capped
+		9: 5 * time.Second,
+	} {
+		var longest time.Duration
+		for i := 0; i < 200; i++ {
+			d := p.Backoff(attempt)
+			if d <= 0 || d > limit {
+				t.Fatalf("Backoff(%d) = %s, want within (0, %s]", attempt, d, limit)
+			}
+			longest = max(longest, d)
+		}
+		// Full jitter spreads the delays over the whole range
+		if longest < limit/2 {
+			t.Errorf("Backoff(%d) never exceeded %s in 200 tries", attempt, longest)
+		}
+	}
+	if d := (RetryPolicy{}).Backoff(3); d != 0 {
+		t.Errorf("zero policy Backoff = %s, want 0", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 17, 6, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	} {
+		if got := ParseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{fmt.Errorf("get: %w", context.DeadlineExceeded), false},
+		{errors.New("connection reset by peer"), true},
+		{&StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{&StatusError{StatusCode: http.StatusRequestTimeout}, true},
+		{&StatusError{StatusCode: http.StatusBadGateway}, true},
+		{&StatusError{StatusCode: http.StatusNotFound}, false},
+		{&StatusError{StatusCode: http.StatusUnauthorized}, false},
+		{Permanent(errors.New("no such record")), false},
+		{fmt.Errorf("query: %w", Permanent(errors.New("bad JSON"))), false},
+	} {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 4 * time.Second, MaxRetryAfter: time.Minute}
+	for _, tt := range []struct {
+		name string
+		err  error
+		want time.Duration // 0 for a backoff delay
+		ok   bool
+	}{
+		{"429 with Retry-After", &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 30 * time.Second}, 30 * time.Second, true},
+		{"503 with Retry-After", &StatusError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 5 * time.Second}, 5 * time.Second, true},
+		{"Retry-After too long", &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}, 0, false},
+		{"500 ignores Retry-After", &StatusError{StatusCode: http.StatusInternalServerError, RetryAfter: time.Hour}, 0, true},
+		{"transport error", errors.New("EOF"), 0, true},
+	} {
+		got, ok := p.Delay(2, tt.err)
+		switch {
+		case ok != tt.ok:
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+		case tt.want != 0 && got != tt.want:
+			t.Errorf("%s: delay = %s, want %s", tt.name, got, tt.want)
+		case tt.want == 0 && ok && (got <= 0 || got > 2*time.Second):
+			t.Errorf("%s: delay = %s, want a backoff of at most 2s", tt.name, got)
+		}
+	}
+}
+
+func TestFetchStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{Client: srv.Client()}
+	resp, err := f.Fetch(context.Background(), srv.URL+"/idea/nook", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Fetch error = %v, want a *StatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusTooManyRequests || statusErr.RetryAfter != 7*time.Second {
+		t.Errorf("StatusError = %+v", statusErr)
+	}
+	if resp == nil || resp.Body != "slow down\n" {
+		t.Errorf("error page = %+v", resp)
+	}
+}
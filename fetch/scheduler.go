@@ -0,0 +1,115 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Job is one page to fetch. Index identifies the page in the caller's list.
+type Job struct {
+	Index int
+	URL   string
+}
+
+// Result is the outcome of a Job.
+type Result struct {
+	Job
+	Body string
+	Err  error
+}
+
+// Scheduler runs jobs on a bounded pool of workers. Every job first waits on
+// the shared limiter, so the request rate holds across all workers and across
+// every Run that shares the Scheduler.
+type Scheduler struct {
+	Concurrency int
+	Limiter     *rate.Limiter
+}
+
+// NewScheduler returns a Scheduler with concurrency workers and a token
+// bucket allowing limit requests per second (burst 1).
+func NewScheduler(concurrency int, limit rate.Limit) *Scheduler {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Scheduler{
+		Concurrency: concurrency,
+		Limiter:     rate.NewLimiter(limit, 1),
+	}
+}
+
+// Run calls fetch for every job and returns the results in job order. It
+// stops handing out jobs once ctx is done; unstarted jobs report ctx.Err().
+func (s *Scheduler) Run(ctx context.Context, jobs []Job, fetch func(context.Context, Job) (string, error)) []Result {
+	results := make([]Result, len(jobs))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.Concurrency, len(jobs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = s.do(ctx, jobs[i], fetch)
+			}
+		}()
+	}
+
+	for i := range jobs {
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			results[i] = Result{Job: jobs[i], Err: ctx.Err()}
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+func (s *Scheduler) do(ctx context.Context, job Job, fetch func(context.Context, Job) (string, error)) Result {
+	if s.Limiter != nil {
+		if err := s.Limiter.Wait(ctx); err != nil {
+			return Result{Job: job, Err: err}
+		}
+	}
+	body, err := fetch(ctx, job)
+	return Result{Job: job, Body: body, Err: err}
+}
+
+// ParseRate parses a request rate such as "2/s", "30/m", "0.5" (per second)
+// or "inf" for no limit. A rate of zero is an error rather than no limit.
+func ParseRate(s string) (rate.Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "inf" {
+		return rate.Inf, nil
+	}
+
+	count, unit, found := strings.Cut(s, "/")
+	per := time.Second
+	if found {
+		switch unit {
+		case "s", "sec":
+			per = time.Second
+		case "m", "min":
+			per = time.Minute
+		case "h":
+			per = time.Hour
+		default:
+			return 0, fmt.Errorf("invalid rate unit %q (use s, m or h)", unit)
+		}
+	}
+
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate %q (must be above zero, or inf for no limit)", s)
+	}
+	return rate.Limit(n / per.Seconds()), nil
+}
@@ -0,0 +1,125 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestSchedulerRun(t *testing.T) {
+	s := NewScheduler(3, rate.Inf)
+	jobs := make([]Job, 20)
+	for i := range jobs {
+		jobs[i] = Job{Index: i, URL: fmt.Sprintf("/page/%d", i)}
+	}
+
+	var running, peak atomic.Int32
+	results := s.Run(context.Background(), jobs, func(ctx context.Context, job Job) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		// Later jobs finish first, so job order is not completion order
+		time.Sleep(time.Duration(len(jobs)-job.Index) * time.Millisecond)
+		if job.Index == 7 {
+			return "", errors.New("boom")
+		}
+		return job.URL, nil
+	})
+
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency = %d, want 2 or 3", p)
+	}
+	if len(results) != len(jobs) {
+		t.Fatalf("got %d results, want %d", len(results), len(jobs))
+	}
+	for i, r := range results {
+		switch {
+		case r.Index != i:
+			t.Errorf("results[%d] is job %d", i, r.Index)
+		case i == 7 && (r.Err == nil || r.Body != ""):
+			t.Errorf("results[7] = %+v, want the error", r)
+		case i != 7 && (r.Err != nil || r.Body != jobs[i].URL):
+			t.Errorf("results[%d] = %+v", i, r)
+		}
+	}
+}
+
+func TestSchedulerRateLimit(t *testing.T) {
+	// Burst 1 at 50/s: five jobs take at least four intervals of 20ms,
+	// however many workers share them
+	s := NewScheduler(5, 50)
+	jobs := make([]Job, 5)
+	for i := range jobs {
+		jobs[i] = Job{Index: i}
+	}
+	start := time.Now()
+	s.Run(context.Background(), jobs, func(context.Context, Job) (string, error) { return "", nil })
+	if elapsed := time.Since(start); elapsed < 75*time.Millisecond {
+		t.Errorf("5 jobs at 50/s took %s", elapsed)
+	}
+}
+
+func TestSchedulerRunCancel(t *testing.T) {
+	s := NewScheduler(1, rate.Inf)
+	jobs := make([]Job, 5)
+	for i := range jobs {
+		jobs[i] = Job{Index: i}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	results := s.Run(ctx, jobs, func(ctx context.Context, job Job) (string, error) {
+		calls.Add(1)
+		cancel()
+		return "done", nil
+	})
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fetch called %d times, want 1", n)
+	}
+	if results[0].Err != nil || results[0].Body != "done" {
+		t.Errorf("results[0] = %+v", results[0])
+	}
+	for _, r := range results[1:] {
+		if !errors.Is(r.Err, context.Canceled) || r.Index == 0 {
+			t.Errorf("unstarted job = %+v, want context.Canceled", r)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want rate.Limit
+	}{
+		{"2/s", 2},
+		{"2/sec", 2},
+		{"30/m", 0.5},
+		{"30/min", 0.5},
+		{"3600/h", 1},
+		{"0.5", 0.5},
+		{" 4 ", 4},
+		{"inf", rate.Inf},
+	} {
+		got, err := ParseRate(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "fast", "2/d", "-1/s", "0", "0/m", "/s"} {
+		if got, err := ParseRate(in); err == nil {
+			t.Errorf("ParseRate(%q) = %v, want an error", in, got)
+		}
+	}
+}
@@ -2,4 +2,18 @@ module github.com/rubinkazan/ideabrowser-scraper
 
 go 1.22.2
 
-require github.com/joho/godotenv v1.5.1
+require (
+	al.essio.dev/pkg/shellescape v1.5.1 // indirect
+	filippo.io/age v1.2.1
+	github.com/andybalholm/cascadia v1.3.2
+	github.com/danieljoos/wincred v1.2.2 // indirect
+	github.com/godbus/dbus/v5 v5.1.0 // indirect
+	github.com/joho/godotenv v1.5.1
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	github.com/zalando/go-keyring v0.2.6
+	golang.org/x/crypto v0.27.0 // indirect
+	golang.org/x/net v0.29.0
+	golang.org/x/sys v0.26.0 // indirect
+	golang.org/x/time v0.8.0
+)
@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"sort"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+// checkLayout fingerprints the scraped pages and compares each with the
+// known-good fingerprint kept in outputDir, returning one warning per page
+// whose layout drifted. The known-good fingerprint stays fixed, so small
+// changes that each pass the tolerance still add up to drift over several
+// runs; only pages seen for the first time are recorded, and with
+// Config.AcceptLayout every page is. Problems with the fingerprint file are
+// logged, never fatal.
+func (c *Client) checkLayout(pages map[string]string, outputDir string) []string {
+	known, err := storage.LoadFingerprints(outputDir)
+	if err != nil {
+		c.logger.Printf("Warning: layout check skipped: %v", err)
+		return nil
+	}
+
+	keys := make([]string, 0, len(pages))
+	for key := range pages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	updated := make(map[string]*model.Fingerprint)
+	for _, key := range keys {
+		current := extract.Fingerprint(key, pages[key])
+		good, ok := known[key]
+		if !ok || c.acceptLayout {
+			updated[key] = current
+			continue
+		}
+		if drift := extract.CompareLayout(key, good, current, c.layoutTolerance); drift != nil {
+			warnings = append(warnings, drift.String())
+		}
+	}
+	if len(updated) == 0 {
+		return warnings
+	}
+
+	for key, fp := range updated {
+		known[key] = fp
+	}
+	if err := storage.SaveFingerprints(outputDir, known); err != nil {
+		c.logger.Printf("Warning: failed to save layout fingerprints: %v", err)
+	}
+	return warnings
+}
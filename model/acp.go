@@ -0,0 +1,156 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ACPData is the Audience, Community, Product analysis of an idea, with one
+// field per labelled item on the ACP page.
+type ACPData struct {
+	Audience      ACPAudience      `json:"audience"`
+	Community     ACPCommunity     `json:"community"`
+	Product       ACPProduct       `json:"product"`
+	ExecutionPlan ACPExecutionPlan `json:"execution_plan"`
+}
+
+// ACPAudience is the AUDIENCE ANALYSIS section.
+type ACPAudience struct {
+	Demographics    string `json:"demographics,omitempty"`
+	Psychographics  string `json:"psychographics,omitempty"`
+	Platforms       string `json:"platforms,omitempty"`
+	UnmetNeeds      string `json:"unmet_needs,omitempty"`
+	ContentGaps     string `json:"content_gaps,omitempty"`
+	Differentiation string `json:"differentiation,omitempty"`
+	SecretSauce     string `json:"secret_sauce,omitempty"`
+	KeyTopics       string `json:"key_topics,omitempty"`
+	ContentFormats  string `json:"content_formats,omitempty"`
+}
+
+// ACPCommunity is the COMMUNITY ANALYSIS section.
+type ACPCommunity struct {
+	PrimaryPlatform    string `json:"primary_platform,omitempty"`
+	PlatformRationale  string `json:"platform_rationale,omitempty"`
+	SecondaryPlatforms string `json:"secondary_platforms,omitempty"`
+	UGCStrategy        string `json:"ugc_strategy,omitempty"`
+	ModerationApproach string `json:"moderation_approach,omitempty"`
+	Transparency       string `json:"transparency,omitempty"`
+	CommunityRituals   string `json:"community_rituals,omitempty"`
+	ContentCalendar    string `json:"content_calendar,omitempty"`
+	InteractionMethods string `json:"interaction_methods,omitempty"`
+}
+
+// ACPProduct is the PRODUCT ANALYSIS section.
+type ACPProduct struct {
+	Description          string `json:"description,omitempty"`
+	KeyFeatures          string `json:"key_features,omitempty"`
+	ValueProposition     string `json:"value_proposition,omitempty"`
+	MVP                  string `json:"mvp,omitempty"`
+	FutureIterations     string `json:"future_iterations,omitempty"`
+	CommunityIntegration string `json:"community_integration,omitempty"`
+	NetworkEffects       string `json:"network_effects,omitempty"`
+	StickyFeatures       string `json:"sticky_features,omitempty"`
+	UsageFrequency       string `json:"usage_frequency,omitempty"`
+}
+
+// ACPExecutionPlan is the EXECUTION PLAN section.
+type ACPExecutionPlan struct {
+	NinetyDayPlan string `json:"ninety_day_plan,omitempty"`
+}
+
+// legacyACP is the version 1 ACP layout, which packed labelled fields into
+// prefixed strings under audience, customer and problem.
+type legacyACP struct {
+	Audience struct {
+		Description  string            `json:"description"`
+		Size         string            `json:"size"`
+		Demographics map[string]string `json:"demographics,omitempty"`
+	} `json:"audience"`
+	Customer struct {
+		Description string   `json:"description"`
+		Segments    []string `json:"segments,omitempty"`
+		Behaviors   []string `json:"behaviors,omitempty"`
+	} `json:"customer"`
+	Problem struct {
+		Description      string   `json:"description"`
+		PainPoints       []string `json:"pain_points,omitempty"`
+		CurrentSolutions []string `json:"current_solutions,omitempty"`
+	} `json:"problem"`
+}
+
+// UnmarshalJSON reads both the current layout and the version 1 layout,
+// converting the latter.
+func (a *ACPData) UnmarshalJSON(data []byte) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	_, customer := keys["customer"]
+	_, problem := keys["problem"]
+	if !customer && !problem {
+		type current ACPData
+		return json.Unmarshal(data, (*current)(a))
+	}
+
+	var old legacyACP
+	if err := json.Unmarshal(data, &old); err != nil {
+		return err
+	}
+	*a = old.upgrade()
+	return nil
+}
+
+// upgrade moves every version 1 field into its own typed field. The
+// hard-coded customer description carried no information and is dropped.
+func (old *legacyACP) upgrade() ACPData {
+	var a ACPData
+
+	demographics := map[string]*string{
+		"primary":         &a.Audience.Demographics,
+		"psychographics":  &a.Audience.Psychographics,
+		"platforms":       &a.Audience.Platforms,
+		"content_gaps":    &a.Audience.ContentGaps,
+		"differentiation": &a.Audience.Differentiation,
+		"secret_sauce":    &a.Audience.SecretSauce,
+		"key_topics":      &a.Audience.KeyTopics,
+		"content_formats": &a.Audience.ContentFormats,
+	}
+	for key, value := range old.Audience.Demographics {
+		if dst, ok := demographics[key]; ok {
+			*dst = value
+		}
+	}
+	a.Audience.UnmetNeeds = old.Audience.Description
+	a.ExecutionPlan.NinetyDayPlan = strings.TrimPrefix(old.Audience.Size, "90-Day Plan: ")
+
+	prefixed := map[string]*string{
+		"Primary Platform":    &a.Community.PrimaryPlatform,
+		"Platform Rationale":  &a.Community.PlatformRationale,
+		"Secondary Platforms": &a.Community.SecondaryPlatforms,
+		"UGC":                 &a.Community.UGCStrategy,
+		"Moderation":          &a.Community.ModerationApproach,
+		"Transparency":        &a.Community.Transparency,
+		"Rituals":             &a.Community.CommunityRituals,
+		"Calendar":            &a.Community.ContentCalendar,
+		"Interaction":         &a.Community.InteractionMethods,
+		"Features":            &a.Product.KeyFeatures,
+		"Value":               &a.Product.ValueProposition,
+		"MVP":                 &a.Product.MVP,
+		"Future":              &a.Product.FutureIterations,
+		"Integration":         &a.Product.CommunityIntegration,
+		"Network Effects":     &a.Product.NetworkEffects,
+		"Sticky Features":     &a.Product.StickyFeatures,
+		"Usage":               &a.Product.UsageFrequency,
+	}
+	for _, list := range [][]string{old.Customer.Segments, old.Customer.Behaviors, old.Problem.PainPoints, old.Problem.CurrentSolutions} {
+		for _, item := range list {
+			prefix, value, ok := strings.Cut(item, ": ")
+			if dst, known := prefixed[prefix]; ok && known {
+				*dst = value
+			}
+		}
+	}
+	a.Product.Description = old.Problem.Description
+
+	return a
+}
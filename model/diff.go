@@ -0,0 +1,119 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change is one field that differs between two extractions of an idea.
+// Old is nil for an added field and New is nil for a removed one.
+type Change struct {
+	// Path is the field's JSON path, such as "framework_fit.value_equation.score"
+	// or "tags[1]".
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// maxDiffValue is the longest value Change.String prints before cutting it.
+const maxDiffValue = 80
+
+// String formats the change as "~ path: old → new", "+ path: new" or
+// "- path: old".
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("+ %s: %s", c.Path, diffValue(c.New))
+	case c.New == nil:
+		return fmt.Sprintf("- %s: %s", c.Path, diffValue(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s → %s", c.Path, diffValue(c.Old), diffValue(c.New))
+}
+
+func diffValue(v interface{}) string {
+	data, _ := json.Marshal(v)
+	s := []rune(string(data))
+	if len(s) > maxDiffValue {
+		return string(s[:maxDiffValue-1]) + "…"
+	}
+	return string(s)
+}
+
+// Diff returns the fields that differ between old and new in their JSON
+// form, in path order. Objects are compared key by key and arrays index by
+// index, so an item inserted into a list shows as changes to the items
+// after it. Fields omitted from the JSON count as absent.
+func Diff(old, new *IdeaData) ([]Change, error) {
+	a, err := genericJSON(old)
+	if err != nil {
+		return nil, err
+	}
+	b, err := genericJSON(new)
+	if err != nil {
+		return nil, err
+	}
+	var out []Change
+	diffJSON("", a, b, &out)
+	return out, nil
+}
+
+func genericJSON(idea *IdeaData) (interface{}, error) {
+	if idea == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(idea)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %v", err)
+	}
+	return v, nil
+}
+
+func diffJSON(path string, a, b interface{}, out *[]Change) {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(a)+len(b))
+			for k := range a {
+				keys = append(keys, k)
+			}
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				diffJSON(p, a[k], b[k], out)
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			for i := 0; i < len(a) || i < len(b); i++ {
+				var x, y interface{}
+				if i < len(a) {
+					x = a[i]
+				}
+				if i < len(b) {
+					y = b[i]
+				}
+				diffJSON(path+"["+strconv.Itoa(i)+"]", x, y, out)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*out = append(*out, Change{Path: path, Old: a, New: b})
+	}
+}
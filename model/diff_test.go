@@ -0,0 +1,43 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+func TestDiff(t *testing.T) {
+	old := &model.IdeaData{Slug: "nook", Title: "Nook", Tags: []string{"home", "reading"}}
+	new := &model.IdeaData{Slug: "nook", Title: "Nook Reading Nooks", Tags: []string{"home"}, Description: "Cozy corners", Warnings: []string{"no ACP data"}}
+
+	changes, err := model.Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Change{
+		{Path: "description", Old: "", New: "Cozy corners"},
+		{Path: "tags[1]", Old: "reading"},
+		{Path: "title", Old: "Nook", New: "Nook Reading Nooks"},
+		{Path: "warnings", New: []interface{}{"no ACP data"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Diff = %+v\nwant %+v", changes, want)
+	}
+
+	lines := []string{
+		`~ description: "" → "Cozy corners"`,
+		`- tags[1]: "reading"`,
+		`~ title: "Nook" → "Nook Reading Nooks"`,
+		`+ warnings: ["no ACP data"]`,
+	}
+	for i, c := range changes {
+		if c.String() != lines[i] {
+			t.Errorf("String() = %q, want %q", c.String(), lines[i])
+		}
+	}
+
+	if changes, _ := model.Diff(old, old); len(changes) != 0 {
+		t.Errorf("Diff of an idea with itself = %+v", changes)
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+// Fingerprint is the structure of a scraped page, recorded to notice when
+// the site's layout changes under the extractors.
+type Fingerprint struct {
+	// Headings are the texts of the page's h1-h4 headings, in page order.
+	Headings []string `json:"headings,omitempty"`
+	// Labels are the texts of its section labels such as
+	// "AUDIENCE ANALYSIS" and field labels such as "Market Size", in page
+	// order.
+	Labels []string `json:"labels,omitempty"`
+	// Anchors are the texts the extractors look for that the page has.
+	Anchors []string `json:"anchors,omitempty"`
+	// Shape is the sorted set of element paths on the page, each the tag
+	// names of an element and its two closest ancestors, such as
+	// "section>div>h3".
+	Shape []string `json:"shape"`
+}
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Value ladder stage kinds, lowest offer first.
+const (
+	LeadMagnet        = "lead_magnet"
+	FrontendOffer     = "frontend"
+	CoreOffer         = "core"
+	ContinuityProgram = "continuity"
+	BackendOffer      = "backend"
+)
+
+// Billing periods of a price.
+const (
+	OneTime = "one_time"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+)
+
+// LTVMonths is how long a customer is assumed to stay subscribed to the
+// recurring stages of a ladder when estimating its lifetime value.
+const LTVMonths = 12
+
+// LadderStage is one offer on the value ladder.
+type LadderStage struct {
+	Kind          string `json:"kind"`
+	Title         string `json:"title"`
+	Price         *Price `json:"price,omitempty"`
+	Description   string `json:"description,omitempty"`
+	ValueProvided string `json:"value_provided,omitempty"`
+	Goal          string `json:"goal,omitempty"`
+}
+
+// Price is a stage price as shown on the page, such as "$19/month", and
+// its parsed parts.
+type Price struct {
+	Text string `json:"text"`
+	// Currency is an ISO 4217 code, empty when the text names none.
+	Currency string  `json:"currency,omitempty"`
+	Amount   float64 `json:"amount"`
+	// Period is OneTime, Monthly or Yearly. Unrecognised periods are read
+	// as OneTime.
+	Period string `json:"period"`
+	Free   bool   `json:"free,omitempty"`
+}
+
+// LadderSummary is derived from the stage prices of a value ladder. Stages
+// priced in another currency than the first paid stage are left out.
+type LadderSummary struct {
+	Currency string `json:"currency,omitempty"`
+	// EntryPrice is the cheapest paid stage and HighestTicket the most
+	// expensive, comparing a year's worth of recurring prices.
+	EntryPrice    *Price `json:"entry_price,omitempty"`
+	HighestTicket *Price `json:"highest_ticket,omitempty"`
+	// EstimatedLTV assumes a customer buys every stage once and keeps the
+	// recurring ones for LTVMonths.
+	EstimatedLTV float64 `json:"estimated_ltv"`
+}
+
+var (
+	amountRe  = regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?)\s*([kKmM]\b)?`)
+	monthlyRe = regexp.MustCompile(`(?i)(/\s*mo\b|/\s*month|per\s+month|a\s+month|monthly)`)
+	yearlyRe  = regexp.MustCompile(`(?i)(/\s*yr\b|/\s*year|per\s+year|a\s+year|yearly|annual)`)
+)
+
+// currencies maps the symbols and codes accepted in prices to ISO 4217
+// codes. Longer symbols come first so "A$" is not read as "$".
+var currencies = []struct{ symbol, code string }{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"A$", "AUD"}, {"C$", "CAD"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₹", "INR"},
+	{"USD", "USD"}, {"EUR", "EUR"}, {"GBP", "GBP"}, {"AUD", "AUD"}, {"CAD", "CAD"},
+}
+
+// ParsePrice parses a price such as "Free", "$29", "$1,499", "$19/month",
+// "€1.2k per year" or "$12,000/year". Ranges such as "$29-$49" take their
+// lower bound. It returns nil for empty text; text without an amount is
+// kept with a zero Amount.
+func ParsePrice(text string) *Price {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil
+	}
+	p := &Price{Text: text, Period: OneTime}
+
+	for _, c := range currencies {
+		if strings.Contains(text, c.symbol) {
+			p.Currency = c.code
+			break
+		}
+	}
+
+	m := amountRe.FindStringSubmatch(text)
+	if m != nil {
+		p.Amount, _ = strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		switch strings.ToLower(m[2]) {
+		case "k":
+			p.Amount *= 1e3
+		case "m":
+			p.Amount *= 1e6
+		}
+	}
+	p.Free = p.Amount == 0 && (m != nil || strings.Contains(strings.ToLower(text), "free"))
+
+	switch {
+	case monthlyRe.MatchString(text):
+		p.Period = Monthly
+	case yearlyRe.MatchString(text):
+		p.Period = Yearly
+	}
+	return p
+}
+
+// yearValue is what the price charges in a year: a one-time price once,
+// a recurring one for twelve months.
+func (p *Price) yearValue() float64 {
+	switch p.Period {
+	case Monthly:
+		return p.Amount * 12
+	default:
+		return p.Amount
+	}
+}
+
+// lifetimeValue is what the price charges a customer staying LTVMonths.
+func (p *Price) lifetimeValue() float64 {
+	switch p.Period {
+	case Monthly:
+		return p.Amount * LTVMonths
+	case Yearly:
+		return p.Amount * LTVMonths / 12
+	default:
+		return p.Amount
+	}
+}
+
+// SummarizeLadder derives the entry price, highest ticket and estimated
+// lifetime value of stages. It returns nil when no stage has a paid price.
+func SummarizeLadder(stages []LadderStage) *LadderSummary {
+	var s *LadderSummary
+	for _, stage := range stages {
+		p := stage.Price
+		if p == nil || p.Free || p.Amount == 0 {
+			continue
+		}
+		if s == nil {
+			s = &LadderSummary{Currency: p.Currency}
+		} else if p.Currency != "" && s.Currency != "" && p.Currency != s.Currency {
+			continue
+		}
+		if s.EntryPrice == nil || p.yearValue() < s.EntryPrice.yearValue() {
+			s.EntryPrice = p
+		}
+		if s.HighestTicket == nil || p.yearValue() > s.HighestTicket.yearValue() {
+			s.HighestTicket = p
+		}
+		s.EstimatedLTV += p.lifetimeValue()
+	}
+	return s
+}
+
+// LadderStages is the list of value ladder stages, lowest offer first.
+type LadderStages []LadderStage
+
+// legacyStageKinds maps the stage labels of the version 1-3 string layout
+// to stage kinds.
+var legacyStageKinds = map[string]string{
+	"Lead Magnet": LeadMagnet,
+	"Frontend":    FrontendOffer,
+	"Core":        CoreOffer,
+	"Continuity":  ContinuityProgram,
+	"Backend":     BackendOffer,
+}
+
+// UnmarshalJSON reads both the current layout and the string layout used
+// before version 4, which formatted each stage as a "Core: Title (Price)"
+// line followed by "  - Description: ...", "  - Value: ..." and
+// "  - Goal: ..." lines.
+func (l *LadderStages) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if json.Unmarshal(data, &lines) != nil {
+		type current LadderStages
+		return json.Unmarshal(data, (*current)(l))
+	}
+
+	stages := LadderStages{}
+	for _, line := range lines {
+		if detail, ok := strings.CutPrefix(line, "  - "); ok {
+			if len(stages) == 0 {
+				continue
+			}
+			stage := &stages[len(stages)-1]
+			label, text, _ := strings.Cut(detail, ": ")
+			switch label {
+			case "Description":
+				stage.Description = text
+			case "Value":
+				stage.ValueProvided = text
+			case "Goal":
+				stage.Goal = text
+			}
+			continue
+		}
+
+		label, title, _ := strings.Cut(line, ": ")
+		stage := LadderStage{Kind: legacyStageKinds[label], Title: title}
+		if strings.HasSuffix(title, ")") {
+			if i := strings.LastIndex(title, " ("); i >= 0 {
+				stage.Title = title[:i]
+				stage.Price = ParsePrice(title[i+2 : len(title)-1])
+			}
+		}
+		stages = append(stages, stage)
+	}
+	*l = stages
+	return nil
+}
@@ -0,0 +1,87 @@
+package model_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		text string
+		want *model.Price
+	}{
+		{"", nil},
+		{"Free", &model.Price{Text: "Free", Period: model.OneTime, Free: true}},
+		{"$0", &model.Price{Text: "$0", Currency: "USD", Period: model.OneTime, Free: true}},
+		{"$29", &model.Price{Text: "$29", Currency: "USD", Amount: 29, Period: model.OneTime}},
+		{"$1,499", &model.Price{Text: "$1,499", Currency: "USD", Amount: 1499, Period: model.OneTime}},
+		{"$19/month", &model.Price{Text: "$19/month", Currency: "USD", Amount: 19, Period: model.Monthly}},
+		{"$9.99 / mo", &model.Price{Text: "$9.99 / mo", Currency: "USD", Amount: 9.99, Period: model.Monthly}},
+		{"$12,000/year", &model.Price{Text: "$12,000/year", Currency: "USD", Amount: 12000, Period: model.Yearly}},
+		{"€1.2k per year", &model.Price{Text: "€1.2k per year", Currency: "EUR", Amount: 1200, Period: model.Yearly}},
+		{"CA$49 billed annually", &model.Price{Text: "CA$49 billed annually", Currency: "CAD", Amount: 49, Period: model.Yearly}},
+		{"$29-$49", &model.Price{Text: "$29-$49", Currency: "USD", Amount: 29, Period: model.OneTime}},
+		{"Custom pricing", &model.Price{Text: "Custom pricing", Period: model.OneTime}},
+	}
+	for _, tt := range tests {
+		if got := model.ParsePrice(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePrice(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeLadder(t *testing.T) {
+	stages := []model.LadderStage{
+		{Kind: model.LeadMagnet, Price: model.ParsePrice("Free")},
+		{Kind: model.FrontendOffer, Price: model.ParsePrice("$29")},
+		{Kind: model.CoreOffer, Price: model.ParsePrice("$1,499")},
+		{Kind: model.ContinuityProgram, Price: model.ParsePrice("$19/month")},
+		{Kind: model.BackendOffer, Price: model.ParsePrice("£900")},
+	}
+	got := model.SummarizeLadder(stages)
+	if got == nil {
+		t.Fatal("SummarizeLadder returned nil")
+	}
+	// The pound-priced backend offer is left out
+	if got.Currency != "USD" || got.EntryPrice.Text != "$29" || got.HighestTicket.Text != "$1,499" {
+		t.Errorf("summary = %s, entry %s, highest %s; want USD, $29, $1,499", got.Currency, got.EntryPrice.Text, got.HighestTicket.Text)
+	}
+	if want := 29 + 1499 + 19.0*model.LTVMonths; got.EstimatedLTV != want {
+		t.Errorf("EstimatedLTV = %v, want %v", got.EstimatedLTV, want)
+	}
+
+	if got := model.SummarizeLadder(stages[:1]); got != nil {
+		t.Errorf("SummarizeLadder of free stages = %+v, want nil", got)
+	}
+}
+
+// testdata/idea_v1.json holds the value ladder lines written before
+// version 4 for extract/testdata/nook/page_5.html.
+func TestUpgradeValueLadder(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "idea_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idea, _, err := model.Upgrade(data)
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join("..", "extract", "testdata", "nook", "page_5.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := extract.Framework(string(page))
+	got := idea.FrameworkFit
+	if !reflect.DeepEqual(got.ValueLadderStages, want.ValueLadderStages) {
+		t.Errorf("upgraded stages = %+v\nwant %+v", got.ValueLadderStages, want.ValueLadderStages)
+	}
+	if !reflect.DeepEqual(got.ValueLadderSummary, want.ValueLadderSummary) {
+		t.Errorf("upgraded summary = %+v\nwant %+v", got.ValueLadderSummary, want.ValueLadderSummary)
+	}
+}
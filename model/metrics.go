@@ -0,0 +1,168 @@
+package model
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Units of a Metric other than currencies, which use ISO 4217 codes.
+const (
+	UnitCount   = "count"
+	UnitPercent = "percent"
+	UnitHours   = "hours"
+	UnitDays    = "days"
+	UnitWeeks   = "weeks"
+	UnitMonths  = "months"
+	UnitYears   = "years"
+)
+
+// Metric is a figure quoted on a page, normalized to a number and a unit.
+type Metric struct {
+	// Label is the heading or label the figure appeared under, such as
+	// "Market Size".
+	Label string `json:"label"`
+	// Text is the figure as written, such as "$2.3B" or "10-20 hours".
+	Text  string  `json:"text"`
+	Value float64 `json:"value"`
+	// Max is the upper bound of a range such as "10-20 hours", where Value
+	// is the lower bound.
+	Max float64 `json:"max,omitempty"`
+	// Unit is a currency code such as "USD", or one of the Unit constants.
+	Unit string `json:"unit"`
+	// Period is "hour", "day", "week", "month" or "year" for rates and
+	// changes such as "45K/mo" or "+120% YoY".
+	Period string `json:"period,omitempty"`
+	// Page is the key of the page the figure is on.
+	Page string `json:"page,omitempty"`
+}
+
+var metricRe = regexp.MustCompile(`(?i)([+-])?([$€£])?(\d[\d,]*(?:\.\d+)?)\s*(thousand|million|billion|trillion|bn|k|m|b)?\b` +
+	`(?:\s*(?:-|–|to)\s*[$€£]?(\d[\d,]*(?:\.\d+)?)\s*(thousand|million|billion|trillion|bn|k|m|b)?\b)?` +
+	`(%)?` +
+	`(?:\s*(hours?|hrs?|days?|weeks?|months?|years?|yrs?)\b)?` +
+	`(?:\s*(?:/\s*|per\s+|a\s+|an\s+)(mo|month|yr|year|wk|week|day|hour|hr)\b)?` +
+	`(?:\s*(yoy|mom|arr|mrr)\b)?`)
+
+var (
+	metricScales = map[string]float64{
+		"k": 1e3, "thousand": 1e3,
+		"m": 1e6, "million": 1e6,
+		"b": 1e9, "bn": 1e9, "billion": 1e9,
+		"trillion": 1e12,
+	}
+	metricCurrencies = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+	metricTimeUnits  = map[string]string{
+		"hour": UnitHours, "hr": UnitHours, "day": UnitDays, "week": UnitWeeks,
+		"month": UnitMonths, "year": UnitYears, "yr": UnitYears,
+	}
+	metricPeriods = map[string]string{
+		"mo": "month", "month": "month", "yr": "year", "year": "year", "wk": "week",
+		"week": "week", "day": "day", "hour": "hour", "hr": "hour",
+		"yoy": "year", "arr": "year", "mom": "month", "mrr": "month",
+	}
+)
+
+// ParseMetrics returns the figures in text under label: amounts such as
+// "$2.3B" or "$1M-$10M ARR", counts with a scale or rate such as "45K/mo",
+// percentages such as "+120% YoY" and durations such as "10-20 hours per
+// week". Bare numbers, which are as often years or list counts, are left
+// out.
+func ParseMetrics(label, text string) []Metric {
+	var out []Metric
+	for _, m := range metricRe.FindAllStringSubmatch(text, -1) {
+		sign, currency, low, lowScale, high, highScale := m[1], m[2], m[3], strings.ToLower(m[4]), m[5], strings.ToLower(m[6])
+		percent, timeUnit, rate, change := m[7], strings.ToLower(m[8]), strings.ToLower(m[9]), strings.ToLower(m[10])
+		if currency == "" && lowScale == "" && highScale == "" && percent == "" && timeUnit == "" && rate == "" && change == "" {
+			continue
+		}
+
+		metric := Metric{Label: label, Text: strings.TrimSpace(m[0]), Unit: UnitCount}
+		if lowScale == "" {
+			lowScale = highScale
+		}
+		metric.Value = metricNumber(low, lowScale)
+		if sign == "-" {
+			metric.Value = -metric.Value
+		}
+		if high != "" {
+			metric.Max = metricNumber(high, highScale)
+		}
+
+		switch {
+		case currency != "":
+			metric.Unit = metricCurrencies[currency]
+		case percent != "":
+			metric.Unit = UnitPercent
+		case timeUnit != "":
+			metric.Unit = metricTimeUnits[strings.TrimSuffix(timeUnit, "s")]
+		}
+		if rate != "" {
+			metric.Period = metricPeriods[rate]
+		} else if change != "" {
+			metric.Period = metricPeriods[change]
+		}
+		out = append(out, metric)
+	}
+	return out
+}
+
+func metricNumber(digits, scale string) float64 {
+	n, _ := strconv.ParseFloat(strings.ReplaceAll(digits, ",", ""), 64)
+	if s, ok := metricScales[scale]; ok {
+		n *= s
+	}
+	return n
+}
+
+// CollectMetrics returns the figures quoted across the detail pages of
+// idea, labelled with the field, driver, signal or section they are in.
+func CollectMetrics(idea *IdeaData) []Metric {
+	var out []Metric
+	add := func(page, label, text string) {
+		for _, m := range ParseMetrics(label, text) {
+			m.Page = page
+			out = append(out, m)
+		}
+	}
+	sections := func(page string, sections []Section) {
+		for _, s := range sections {
+			add(page, s.Heading, s.Text)
+		}
+	}
+
+	if f := idea.FounderFit; f != nil {
+		add("founder-fit", "Time Commitment", f.TimeCommitment)
+		add("founder-fit", "Starting Capital", f.StartingCapital)
+		sections("founder-fit", f.Sections)
+	}
+	if w := idea.WhyNow; w != nil {
+		for _, d := range w.Drivers {
+			add("why-now", d.Name, d.Evidence)
+		}
+		sections("why-now", w.Sections)
+	}
+	if p := idea.ProofSignals; p != nil {
+		for _, s := range p.Signals {
+			add("proof-signals", s.Source, s.Evidence)
+		}
+		sections("proof-signals", p.Sections)
+	}
+	if g := idea.MarketGap; g != nil {
+		add("market-gap", "Market Size", g.MarketSize)
+		add("market-gap", "Underserved Segment", g.UnderservedSegment)
+		add("market-gap", "Why Incumbents Miss It", g.IncumbentBlindSpot)
+		sections("market-gap", g.Sections)
+	}
+	if e := idea.ExecutionPlan; e != nil {
+		add("execution-plan", "Time to MVP", e.TimeToMVP)
+		for _, p := range e.Phases {
+			add("execution-plan", p.Name, p.Description)
+		}
+		sections("execution-plan", e.Sections)
+	}
+	if b := idea.BuildInfo; b != nil {
+		sections("build/landing-page", b.Sections)
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+func TestParseMetrics(t *testing.T) {
+	tests := []struct {
+		text string
+		want []model.Metric
+	}{
+		{"$2.3B", []model.Metric{{Text: "$2.3B", Value: 2.3e9, Unit: "USD"}}},
+		{`"reading nook" gets 45K/mo searches`, []model.Metric{{Text: "45K/mo", Value: 45000, Unit: model.UnitCount, Period: "month"}}},
+		{"+120% YoY", []model.Metric{{Text: "+120% YoY", Value: 120, Unit: model.UnitPercent, Period: "year"}}},
+		{"fell -5% MoM", []model.Metric{{Text: "-5% MoM", Value: -5, Unit: model.UnitPercent, Period: "month"}}},
+		{"10-20 hours", []model.Metric{{Text: "10-20 hours", Value: 10, Max: 20, Unit: model.UnitHours}}},
+		{"10–20 hours per week", []model.Metric{{Text: "10–20 hours per week", Value: 10, Max: 20, Unit: model.UnitHours, Period: "week"}}},
+		{"$1M-$10M ARR", []model.Metric{{Text: "$1M-$10M ARR", Value: 1e6, Max: 1e7, Unit: "USD", Period: "year"}}},
+		{"€1.5 million", []model.Metric{{Text: "€1.5 million", Value: 1.5e6, Unit: "EUR"}}},
+		{"20-50K users", []model.Metric{{Text: "20-50K", Value: 20000, Max: 50000, Unit: model.UnitCount}}},
+		{"6 weeks to launch", []model.Metric{{Text: "6 weeks", Value: 6, Unit: model.UnitWeeks}}},
+		{"$1,499 once and $19 a month", []model.Metric{
+			{Text: "$1,499", Value: 1499, Unit: "USD"},
+			{Text: "$19 a month", Value: 19, Unit: "USD", Period: "month"},
+		}},
+		// Bare numbers are years, ranks and counts as often as metrics
+		{"Pre-sell 10 installs by 2025", nil},
+		{"Takes 5 minutes", nil},
+	}
+	for _, tt := range tests {
+		if got := model.ParseMetrics("", tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMetrics(%q) = %+v\nwant %+v", tt.text, got, tt.want)
+		}
+	}
+}
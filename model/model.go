@@ -0,0 +1,108 @@
+// Package model holds the data structures produced by the scraper.
+package model
+
+// SchemaVersion is the version of the IdeaData JSON layout written by this
+// package. Files written before versioning have no schema_version and are
+// treated as version 1; see Upgrade.
+const SchemaVersion = 6
+
+// SlugPattern is the regular expression, without anchors, that an idea's
+// slug matches: words of lower-case letters and digits joined by single
+// hyphens. The IdeaData JSON Schema declares the same pattern for "slug".
+const SlugPattern = `[a-z0-9]+(?:-[a-z0-9]+)*`
+
+// IdeaData represents the complete data structure for an idea
+type IdeaData struct {
+	SchemaVersion int               `json:"schema_version"`
+	Slug          string            `json:"slug"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	Date          string            `json:"date"`
+	Tags          []string          `json:"tags,omitempty"`
+	FrameworkFit  *FrameworkData    `json:"framework_fit,omitempty"`
+	ACP           *ACPData          `json:"acp,omitempty"`
+	BuildInfo     *BuildInfo        `json:"build_info,omitempty"`
+	FounderFit    *FounderFit       `json:"founder_fit,omitempty"`
+	ValueLadder   map[string]string `json:"value_ladder,omitempty"`
+	WhyNow        *WhyNow           `json:"why_now,omitempty"`
+	ProofSignals  *ProofSignals     `json:"proof_signals,omitempty"`
+	MarketGap     *MarketGap        `json:"market_gap,omitempty"`
+	ExecutionPlan *ExecutionPlan    `json:"execution_plan,omitempty"`
+	Metrics       []Metric          `json:"metrics,omitempty"`
+
+	// Links are the outbound links of every page, by page.
+	Links []Link `json:"links,omitempty"`
+
+	// PageAccess holds, per page key, whether the page showed its content
+	// or a wall in its place; see the Access constants.
+	PageAccess map[string]string `json:"page_access,omitempty"`
+
+	// Confidence holds, per headline field ("title", "description",
+	// "date"), how well the page's candidates for it agreed, from 0 to 1.
+	Confidence map[string]float64 `json:"confidence,omitempty"`
+	// Warnings are problems noticed while extracting the idea.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// What a fetched page showed, as recorded in IdeaData.PageAccess. A page
+// that answers 200 may still be a sign-in wall, an upgrade prompt or a
+// "not found" page rendered in place of the idea.
+const (
+	AccessOK             = "ok"
+	AccessAuthRequired   = "auth_required"
+	AccessPlanRestricted = "plan_restricted"
+	AccessNotFound       = "not_found"
+)
+
+// FrameworkData represents the Framework Fit metrics
+type FrameworkData struct {
+	ValueEquation struct {
+		Score       int              `json:"score"`
+		Rating      string           `json:"rating"`
+		Description string           `json:"description,omitempty"`
+		Components  []ValueComponent `json:"components,omitempty"`
+	} `json:"value_equation"`
+	MarketMatrix struct {
+		Position    string `json:"position"`
+		Uniqueness  string `json:"uniqueness"`
+		Value       string `json:"value"`
+		Description string `json:"description,omitempty"`
+	} `json:"market_matrix"`
+	ACPFramework struct {
+		Audience  int `json:"audience_score"`
+		Community int `json:"community_score"`
+		Product   int `json:"product_score"`
+		Overall   int `json:"overall_score"`
+	} `json:"acp_framework"`
+	ValueLadderStages  LadderStages   `json:"value_ladder_stages,omitempty"`
+	ValueLadderSummary *LadderSummary `json:"value_ladder_summary,omitempty"`
+}
+
+// Names of the value equation components, in the order the page lists them.
+const (
+	DreamOutcome        = "Dream Outcome"
+	PerceivedLikelihood = "Perceived Likelihood"
+	TimeDelay           = "Time Delay"
+	EffortSacrifice     = "Effort & Sacrifice"
+)
+
+// ValueComponents lists the value equation component names in page order.
+var ValueComponents = []string{DreamOutcome, PerceivedLikelihood, TimeDelay, EffortSacrifice}
+
+// ValueComponent is one scored part of the value equation.
+type ValueComponent struct {
+	Name        string `json:"name"`
+	Score       int    `json:"score"`
+	MaxScore    int    `json:"max_score"`
+	Description string `json:"description,omitempty"`
+}
+
+// ValueComponent returns the value equation component called name, or nil.
+func (f *FrameworkData) ValueComponent(name string) *ValueComponent {
+	for i := range f.ValueEquation.Components {
+		if f.ValueEquation.Components[i].Name == name {
+			return &f.ValueEquation.Components[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package model
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Block is one headed piece of a detail page: a heading and the text under
+// it, or a short label and its value. Pages are filled block by block with
+// their Add methods, which put known headings into typed fields and keep
+// the rest as Sections.
+type Block struct {
+	Heading string
+	Text    string
+	// Label marks a label and value pair rather than a headed block of
+	// text. Unknown labels are always kept as Sections.
+	Label bool
+	Links []Link
+}
+
+// Section is a headed block of page text that has no typed field.
+type Section struct {
+	Heading string `json:"heading"`
+	Text    string `json:"text"`
+	Links   []Link `json:"links,omitempty"`
+}
+
+// Link is an outbound link: an absolute URL off the site, without tracking
+// parameters, with its anchor text and the key of the page it is on.
+type Link struct {
+	Text string `json:"text,omitempty"`
+	URL  string `json:"url"`
+	Page string `json:"page,omitempty"`
+}
+
+// BuildInfo is the build/landing-page page: the suggested landing page copy
+// and how to build it.
+type BuildInfo struct {
+	Headline        string    `json:"headline,omitempty"`
+	Subheadline     string    `json:"subheadline,omitempty"`
+	CallToAction    string    `json:"call_to_action,omitempty"`
+	SuggestedDomain string    `json:"suggested_domain,omitempty"`
+	TechStack       []string  `json:"tech_stack,omitempty"`
+	Sections        []Section `json:"sections,omitempty"`
+}
+
+// Add files b under its field, or as a Section when the heading is unknown.
+func (b *BuildInfo) Add(block Block) {
+	switch normalizeHeading(block.Heading) {
+	case "headline":
+		b.Headline = block.Text
+	case "subheadline":
+		b.Subheadline = block.Text
+	case "calltoaction", "cta":
+		b.CallToAction = block.Text
+	case "suggesteddomain", "domain":
+		b.SuggestedDomain = block.Text
+	case "techstack":
+		b.TechStack = splitList(block.Text)
+	default:
+		b.Sections = append(b.Sections, block.section())
+	}
+}
+
+// FounderFit is the founder-fit page: who is suited to build the idea.
+type FounderFit struct {
+	IdealFounder    string    `json:"ideal_founder,omitempty"`
+	RequiredSkills  []string  `json:"required_skills,omitempty"`
+	TimeCommitment  string    `json:"time_commitment,omitempty"`
+	StartingCapital string    `json:"starting_capital,omitempty"`
+	Sections        []Section `json:"sections,omitempty"`
+}
+
+// Add files b under its field, or as a Section when the heading is unknown.
+func (f *FounderFit) Add(block Block) {
+	switch normalizeHeading(block.Heading) {
+	case "idealfounder":
+		f.IdealFounder = block.Text
+	case "requiredskills", "skills":
+		f.RequiredSkills = splitList(block.Text)
+	case "timecommitment":
+		f.TimeCommitment = block.Text
+	case "startingcapital":
+		f.StartingCapital = block.Text
+	default:
+		f.Sections = append(f.Sections, block.section())
+	}
+}
+
+// WhyNow is the why-now page: the trends that make the idea timely.
+type WhyNow struct {
+	Drivers       []TrendDriver `json:"drivers,omitempty"`
+	TrendStrength string        `json:"trend_strength,omitempty"`
+	Sections      []Section     `json:"sections,omitempty"`
+}
+
+// TrendDriver is one trend behind the idea and the evidence given for it.
+type TrendDriver struct {
+	Name     string `json:"name"`
+	Evidence string `json:"evidence,omitempty"`
+	Sources  []Link `json:"sources,omitempty"`
+}
+
+// Add files b under its field. Unknown headed blocks are trend drivers;
+// unknown labels are kept as Sections.
+func (w *WhyNow) Add(block Block) {
+	switch {
+	case normalizeHeading(block.Heading) == "trendstrength":
+		w.TrendStrength = block.Text
+	case block.Label:
+		w.Sections = append(w.Sections, block.section())
+	default:
+		w.Drivers = append(w.Drivers, TrendDriver{Name: block.Heading, Evidence: block.Text, Sources: block.Links})
+	}
+}
+
+// ProofSignals is the proof-signals page: evidence of demand for the idea.
+type ProofSignals struct {
+	Signals        []Signal  `json:"signals,omitempty"`
+	SignalStrength string    `json:"signal_strength,omitempty"`
+	Sections       []Section `json:"sections,omitempty"`
+}
+
+// Signal is one source of demand evidence, with the figures quoted in it.
+type Signal struct {
+	Source   string   `json:"source"`
+	Evidence string   `json:"evidence,omitempty"`
+	Metrics  []string `json:"metrics,omitempty"`
+	Links    []Link   `json:"links,omitempty"`
+}
+
+// Add files b under its field. Unknown headed blocks are signals; unknown
+// labels are kept as Sections.
+func (p *ProofSignals) Add(block Block) {
+	switch {
+	case normalizeHeading(block.Heading) == "signalstrength":
+		p.SignalStrength = block.Text
+	case block.Label:
+		p.Sections = append(p.Sections, block.section())
+	default:
+		p.Signals = append(p.Signals, Signal{
+			Source:   block.Heading,
+			Evidence: block.Text,
+			Metrics:  metrics(block.Text),
+			Links:    block.Links,
+		})
+	}
+}
+
+// MarketGap is the market-gap page: who the market leaves unserved.
+type MarketGap struct {
+	UnderservedSegment string    `json:"underserved_segment,omitempty"`
+	IncumbentBlindSpot string    `json:"incumbent_blind_spot,omitempty"`
+	MarketSize         string    `json:"market_size,omitempty"`
+	Sections           []Section `json:"sections,omitempty"`
+}
+
+// Add files b under its field, or as a Section when the heading is unknown.
+func (m *MarketGap) Add(block Block) {
+	switch normalizeHeading(block.Heading) {
+	case "underservedsegment":
+		m.UnderservedSegment = block.Text
+	case "whyincumbentsmissit", "incumbentblindspot":
+		m.IncumbentBlindSpot = block.Text
+	case "marketsize":
+		m.MarketSize = block.Text
+	default:
+		m.Sections = append(m.Sections, block.section())
+	}
+}
+
+// ExecutionPlan is the execution-plan page: the phased plan to launch.
+type ExecutionPlan struct {
+	Phases    []Phase   `json:"phases,omitempty"`
+	TimeToMVP string    `json:"time_to_mvp,omitempty"`
+	Sections  []Section `json:"sections,omitempty"`
+}
+
+// Phase is one numbered step of the execution plan.
+type Phase struct {
+	Number      int    `json:"number"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// phaseRe matches phase headings such as "Phase 1: Validate" or
+// "Step 2 - Launch".
+var phaseRe = regexp.MustCompile(`(?i)^(?:phase|step|stage|week|month)\s+(\d+)\s*[:.\-–—]\s*(.+)$`)
+
+// Add files b under its field: numbered headings are phases, and other
+// unknown headings are kept as Sections.
+func (e *ExecutionPlan) Add(block Block) {
+	if normalizeHeading(block.Heading) == "timetomvp" {
+		e.TimeToMVP = block.Text
+		return
+	}
+	if m := phaseRe.FindStringSubmatch(block.Heading); m != nil && !block.Label {
+		n, _ := strconv.Atoi(m[1])
+		e.Phases = append(e.Phases, Phase{Number: n, Name: m[2], Description: block.Text})
+		return
+	}
+	e.Sections = append(e.Sections, block.section())
+}
+
+// metrics returns the figures quoted in text, as written.
+func metrics(text string) []string {
+	var out []string
+	for _, m := range ParseMetrics("", text) {
+		out = append(out, m.Text)
+	}
+	return out
+}
+
+func (b Block) section() Section {
+	return Section{Heading: b.Heading, Text: b.Text, Links: b.Links}
+}
+
+// normalizeHeading lowercases a heading and drops everything but letters
+// and digits, so "Call to Action" and "call-to-action" compare equal.
+func normalizeHeading(h string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, h)
+}
+
+// splitList splits a list separated by commas, semicolons or line breaks,
+// such as "Next.js, Stripe, Calendly", dropping full stops at item ends.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' || r == '\n' }) {
+		if item = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(item), ".")); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
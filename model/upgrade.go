@@ -0,0 +1,150 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Upgrade decodes idea JSON of any schema version and returns it in the
+// current layout, with SchemaVersion set to SchemaVersion. The returned bool
+// reports whether the input was older than the current version.
+func Upgrade(data []byte) (*IdeaData, bool, error) {
+	var version struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, false, err
+	}
+	if version.SchemaVersion > SchemaVersion {
+		return nil, false, fmt.Errorf("schema version %d is newer than supported version %d", version.SchemaVersion, SchemaVersion)
+	}
+
+	// Before version 5 the detail pages were maps of heading to text
+	var idea IdeaData
+	if version.SchemaVersion < 5 {
+		old := legacyIdea{IdeaData: &idea}
+		if err := json.Unmarshal(data, &old); err != nil {
+			return nil, false, err
+		}
+		old.upgradePages()
+	} else if err := json.Unmarshal(data, &idea); err != nil {
+		return nil, false, err
+	}
+
+	// Version 1 files predate schema_version; ACPData converts their layout
+	// while decoding, as LadderStages does for the value ladder lines
+	// written before version 4. Before version 3 the value equation
+	// components were folded into its description.
+	if fw := idea.FrameworkFit; fw != nil {
+		if idea.SchemaVersion < 3 {
+			upgradeValueEquation(fw)
+		}
+		if idea.SchemaVersion < 4 {
+			fw.ValueLadderSummary = SummarizeLadder(fw.ValueLadderStages)
+		}
+	}
+
+	// Metrics were never filled before version 6
+	if idea.SchemaVersion < 6 {
+		idea.Metrics = CollectMetrics(&idea)
+	}
+
+	upgraded := idea.SchemaVersion < SchemaVersion
+	idea.SchemaVersion = SchemaVersion
+	return &idea, upgraded, nil
+}
+
+// valueSummaryRe matches the first line of the value equation description
+// written before version 3, such as
+// "Dream: 9/10, Likelihood: 7/10, Time: 8/10, Effort: 6/10". Scores that were
+// not found are empty.
+var valueSummaryRe = regexp.MustCompile(`^Dream: (\d*)/10, Likelihood: (\d*)/10, Time: (\d*)/10, Effort: (\d*)/10`)
+
+// upgradeValueEquation rebuilds the value equation components from the
+// description string that held them before version 3, followed by one
+// "Name: description" paragraph per component.
+func upgradeValueEquation(f *FrameworkData) {
+	ve := &f.ValueEquation
+	m := valueSummaryRe.FindStringSubmatch(ve.Description)
+	if m == nil || len(ve.Components) > 0 {
+		return
+	}
+
+	descriptions := make(map[string]string)
+	for _, para := range strings.Split(ve.Description, "\n\n")[1:] {
+		if name, text, ok := strings.Cut(para, ": "); ok {
+			descriptions[name] = text
+		}
+	}
+	for i, name := range ValueComponents {
+		score, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			continue
+		}
+		ve.Components = append(ve.Components, ValueComponent{
+			Name:        name,
+			Score:       score,
+			MaxScore:    10,
+			Description: descriptions[name],
+		})
+	}
+	ve.Description = ""
+}
+
+// legacyIdea decodes idea JSON written before version 5, whose detail
+// pages were flat maps from heading to text. Its fields take precedence
+// over the embedded ones of the same name.
+type legacyIdea struct {
+	*IdeaData
+	BuildInfo     map[string]string `json:"build_info"`
+	FounderFit    map[string]string `json:"founder_fit"`
+	WhyNow        map[string]string `json:"why_now"`
+	ProofSignals  map[string]string `json:"proof_signals"`
+	MarketGap     map[string]string `json:"market_gap"`
+	ExecutionPlan map[string]string `json:"execution_plan"`
+}
+
+// upgradePages fills the typed detail pages of the idea from the heading
+// maps. The maps kept no order, so blocks are added by heading.
+func (old *legacyIdea) upgradePages() {
+	fill := func(m map[string]string, add func(Block)) {
+		headings := make([]string, 0, len(m))
+		for h := range m {
+			headings = append(headings, h)
+		}
+		sort.Strings(headings)
+		for _, h := range headings {
+			add(Block{Heading: h, Text: m[h]})
+		}
+	}
+
+	idea := old.IdeaData
+	if len(old.BuildInfo) > 0 {
+		idea.BuildInfo = &BuildInfo{}
+		fill(old.BuildInfo, idea.BuildInfo.Add)
+	}
+	if len(old.FounderFit) > 0 {
+		idea.FounderFit = &FounderFit{}
+		fill(old.FounderFit, idea.FounderFit.Add)
+	}
+	if len(old.WhyNow) > 0 {
+		idea.WhyNow = &WhyNow{}
+		fill(old.WhyNow, idea.WhyNow.Add)
+	}
+	if len(old.ProofSignals) > 0 {
+		idea.ProofSignals = &ProofSignals{}
+		fill(old.ProofSignals, idea.ProofSignals.Add)
+	}
+	if len(old.MarketGap) > 0 {
+		idea.MarketGap = &MarketGap{}
+		fill(old.MarketGap, idea.MarketGap.Add)
+	}
+	if len(old.ExecutionPlan) > 0 {
+		idea.ExecutionPlan = &ExecutionPlan{}
+		fill(old.ExecutionPlan, idea.ExecutionPlan.Add)
+	}
+}
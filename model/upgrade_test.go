@@ -0,0 +1,110 @@
+package model_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// testdata/idea_v1.json was written by the scraper before schema versioning,
+// from the same ACP page as extract/testdata/nook/page_2.html.
+func TestUpgradeACP(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "idea_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idea, upgraded, err := model.Upgrade(data)
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+	if !upgraded || idea.SchemaVersion != model.SchemaVersion {
+		t.Errorf("upgraded = %v, schema version = %d; want true, %d", upgraded, idea.SchemaVersion, model.SchemaVersion)
+	}
+
+	page, err := os.ReadFile(filepath.Join("..", "extract", "testdata", "nook", "page_2.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := extract.ACP(string(page)); !reflect.DeepEqual(idea.ACP, want) {
+		got, _ := json.MarshalIndent(idea.ACP, "", "  ")
+		t.Errorf("upgraded ACP differs from a fresh extraction:\n%s", got)
+	}
+
+	// Upgrading the current layout is a no-op
+	current, err := json.Marshal(idea)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, upgraded, err := model.Upgrade(current)
+	if err != nil {
+		t.Fatalf("Upgrade(current): %v", err)
+	}
+	if upgraded || !reflect.DeepEqual(again, idea) {
+		t.Errorf("Upgrade(current) changed the idea (upgraded = %v)", upgraded)
+	}
+}
+
+func TestUpgradeRejectsNewerVersion(t *testing.T) {
+	if _, _, err := model.Upgrade([]byte(`{"schema_version": 999, "slug": "x"}`)); err == nil {
+		t.Error("Upgrade accepted a newer schema version")
+	}
+}
+
+// Before version 3 the value equation components were folded into its
+// description string.
+func TestUpgradeValueEquation(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "idea_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idea, _, err := model.Upgrade(data)
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join("..", "extract", "testdata", "nook", "page_3.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := extract.Framework(string(page)).ValueEquation
+	if got := idea.FrameworkFit.ValueEquation; !reflect.DeepEqual(got, want) {
+		t.Errorf("upgraded value equation = %+v\nwant %+v", got, want)
+	}
+}
+
+// Before version 5 the detail pages were maps of heading to text.
+func TestUpgradeDetailPages(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "idea_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idea, _, err := model.Upgrade(data)
+	if err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+
+	want := &model.WhyNow{
+		Drivers: []model.TrendDriver{
+			{Name: "Apartment Sizes Shrinking", Evidence: "Median new apartment size fell 5% since 2018."},
+			{Name: "Remote Work Is Permanent", Evidence: "58% of workers now work remotely at least part time ("},
+			{Name: "Search Growth", Evidence: "+120% YoY"},
+		},
+		TrendStrength: "Strong",
+	}
+	if !reflect.DeepEqual(idea.WhyNow, want) {
+		t.Errorf("why now = %+v\nwant %+v", idea.WhyNow, want)
+	}
+
+	// Headings without a typed field are kept as sections
+	if f := idea.FounderFit; f == nil || len(f.Sections) != 3 || !reflect.DeepEqual(f.Sections[2], model.Section{Heading: "Revenue Potential", Text: "$1M-$10M ARR"}) {
+		t.Errorf("founder fit = %+v", idea.FounderFit)
+	}
+	if idea.BuildInfo != nil || idea.ExecutionPlan != nil {
+		t.Errorf("pages missing from the file were upgraded to %+v, %+v", idea.BuildInfo, idea.ExecutionPlan)
+	}
+}
@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+// ArchivedIdea is the latest successfully fetched copy of each page of an
+// idea, as kept in an archive.
+type ArchivedIdea struct {
+	Slug string
+	// Site is the site the newest of the pages was fetched from.
+	Site *url.URL
+	// FetchedAt is when the newest of the pages was fetched.
+	FetchedAt time.Time
+	// Pages are the page bodies keyed by PageKey, as ScrapeIdea returns
+	// them.
+	Pages map[string]string
+}
+
+// ArchivedIdeas gathers the ideas described by entries, taking the latest
+// 200 response for each page, and loads their pages from a. Ideas are
+// returned in slug order.
+func ArchivedIdeas(a *storage.Archive, entries []storage.ArchiveEntry) ([]*ArchivedIdea, error) {
+	latest := make(map[string]map[string]storage.ArchiveEntry)
+	for _, e := range entries {
+		if e.Status != http.StatusOK || e.Slug == "" {
+			continue
+		}
+		pages := latest[e.Slug]
+		if pages == nil {
+			pages = make(map[string]storage.ArchiveEntry)
+			latest[e.Slug] = pages
+		}
+		if prev, ok := pages[e.Page]; !ok || !e.FetchedAt.Before(prev.FetchedAt) {
+			pages[e.Page] = e
+		}
+	}
+
+	slugs := make([]string, 0, len(latest))
+	for slug := range latest {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	ideas := make([]*ArchivedIdea, 0, len(slugs))
+	for _, slug := range slugs {
+		idea := &ArchivedIdea{Slug: slug, Pages: make(map[string]string)}
+		for key, e := range latest[slug] {
+			body, err := a.Open(e.SHA256)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s page %s: %v", slug, key, err)
+			}
+			idea.Pages[key] = string(body)
+			if e.FetchedAt.After(idea.FetchedAt) {
+				idea.FetchedAt = e.FetchedAt
+				if idea.Site, err = url.Parse(e.URL); err != nil {
+					return nil, fmt.Errorf("failed to load %s page %s: %v", slug, key, err)
+				}
+				idea.Site = &url.URL{Scheme: idea.Site.Scheme, Host: idea.Site.Host}
+			}
+		}
+		ideas = append(ideas, idea)
+	}
+	return ideas, nil
+}
+
+// Reparse parses the archived pages of an idea with the current extractors
+// and returns how the result differs from the previous extraction saved in
+// outputDir: the JSON file from the day the pages were fetched, or else the
+// latest one. Unless dryRun is set the new extraction then replaces it
+// through store, under the fetch date. Layout fingerprints are left alone,
+// as archived pages may predate the known-good layout.
+func Reparse(ctx context.Context, store *Store, a *ArchivedIdea, outputDir string, dryRun bool) ([]model.Change, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	idea := extract.Parse(a.Site, a.Slug, a.Pages)
+
+	var previous *model.IdeaData
+	if file := storage.FindJSON(outputDir, a.Slug, a.FetchedAt); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if previous, _, err = model.Upgrade(data); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+	}
+	changes, err := model.Diff(previous, idea)
+	if err != nil || dryRun {
+		return changes, err
+	}
+	return changes, store.Save(ctx, idea, outputDir, a.FetchedAt)
+}
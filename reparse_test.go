@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+// TestReparse archives the saved pages of extract/testdata/nook, saves a
+// stale extraction of them and checks that a dry run only reports the
+// difference while a real run replaces the file with the current one.
+func TestReparse(t *testing.T) {
+	const slug = "nook-smart-reading-nooks"
+	ctx := context.Background()
+	dir := t.TempDir()
+	a, err := storage.OpenArchive(filepath.Join(dir, "archive"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched := time.Date(2025, 1, 17, 6, 0, 0, 0, time.UTC)
+	pages := nookPages(t, slug)
+	for key, body := range pages {
+		if _, err := a.Put(storage.ArchiveEntry{Slug: slug, Page: key, URL: DefaultBaseURL + "/idea/" + slug, FetchedAt: fetched, Status: 200}, []byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A later error page does not replace the good copy
+	if _, err := a.Put(storage.ArchiveEntry{Slug: slug, Page: "acp", FetchedAt: fetched.Add(time.Hour), Status: 500}, []byte("oops")); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := a.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ideas, err := ArchivedIdeas(a, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ideas) != 1 || ideas[0].Slug != slug || !ideas[0].FetchedAt.Equal(fetched) || ideas[0].Pages["acp"] != pages["acp"] || ideas[0].Site.String() != DefaultBaseURL {
+		t.Fatalf("ArchivedIdeas = %+v", ideas)
+	}
+
+	stale := extract.Parse(ideas[0].Site, slug, pages)
+	stale.Title = "Old Title"
+	if _, err := storage.WriteJSON(dir, stale, fetched); err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, storage.JSONFilename(slug, fetched))
+	before, _ := os.ReadFile(jsonPath)
+
+	store := &Store{Logger: log.New(io.Discard, "", 0)}
+	changes, err := Reparse(ctx, store, ideas[0], dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || !strings.HasPrefix(changes[0].String(), `~ title: "Old Title" → `) {
+		t.Fatalf("dry run changes = %v", changes)
+	}
+	if after, _ := os.ReadFile(jsonPath); !bytes.Equal(after, before) {
+		t.Error("dry run rewrote the JSON file")
+	}
+
+	if _, err := Reparse(ctx, store, ideas[0], dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if changes, err := Reparse(ctx, store, ideas[0], dir, true); err != nil || len(changes) != 0 {
+		t.Errorf("changes after reparse = %v, %v", changes, err)
+	}
+	if _, err := os.Stat(storage.ReportPath(jsonPath)); err != nil {
+		t.Errorf("no report written: %v", err)
+	}
+}
@@ -0,0 +1,403 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+)
+
+// MainPage is the page key of the public idea-of-the-day page.
+const MainPage = "/idea-of-the-day"
+
+// PageURLs returns the site paths scraped for an idea, in order. The first
+// page is the idea's overview: the idea-of-the-day page when slug is today's
+// idea, or /idea/<slug> for any other idea.
+func PageURLs(slug string, today bool) []string {
+	mainPage := path.Join("/idea", slug)
+	if today {
+		mainPage = MainPage
+	}
+
+	// Define pages to scrape based on actual available sections
+	return []string{
+		mainPage,                        // Page 1: Overview page
+		path.Join("/idea", slug, "acp"), // Page 2: ACP Framework (requires auth)
+		path.Join("/idea", slug, "value-equation"),     // Page 3: Value Equation
+		path.Join("/idea", slug, "value-matrix"),       // Page 4: Market Matrix
+		path.Join("/idea", slug, "value-ladder"),       // Page 5: Value ladder
+		path.Join("/idea", slug, "build/landing-page"), // Page 6: Build landing page
+		path.Join("/idea", slug, "founder-fit"),        // Page 7: Founder fit
+		path.Join("/idea", slug, "why-now"),            // Page 8: Why now
+		path.Join("/idea", slug, "proof-signals"),      // Page 9: Proof signals
+		path.Join("/idea", slug, "market-gap"),         // Page 10: Market gap
+		path.Join("/idea", slug, "execution-plan"),     // Page 11: Execution plan
+	}
+}
+
+// PageKey returns the key a page path is stored under in the scraped pages
+// map: the path relative to the idea, or MainPage for the overview page.
+func PageKey(slug, pagePath string) string {
+	ideaPath := path.Join("/idea", slug)
+	if pagePath == ideaPath {
+		return MainPage
+	}
+	return strings.TrimPrefix(pagePath, ideaPath+"/")
+}
+
+// GetIdeaSlug returns the slug of today's idea from the public page.
+func (c *Client) GetIdeaSlug(ctx context.Context) (string, error) {
+	htmlContent, err := c.fetcher.Get(ctx, c.baseURL+MainPage, nil)
+	if err != nil {
+		var statusErr *fetch.StatusError
+		if errors.As(err, &statusErr) {
+			return "", fmt.Errorf("failed to fetch idea of the day: status %d", statusErr.StatusCode)
+		}
+		return "", err
+	}
+
+	slug := extract.IdeaSlug(htmlContent)
+	if slug == "" {
+		return "", fmt.Errorf("could not find idea slug in HTML")
+	}
+	c.debugf("Extracted slug: %s", slug)
+	return slug, nil
+}
+
+// DefaultArchivePaths are the listing pages searched for past ideas by
+// DiscoverIdeaSlugs when no paths are given.
+var DefaultArchivePaths = []string{"/database"}
+
+// DiscoverIdeaSlugs returns the slugs of every idea linked from the given
+// listing pages (DefaultArchivePaths when empty), in order of appearance.
+func (c *Client) DiscoverIdeaSlugs(ctx context.Context, archivePaths []string) ([]string, error) {
+	if len(archivePaths) == 0 {
+		archivePaths = DefaultArchivePaths
+	}
+
+	var slugs []string
+	seen := make(map[string]bool)
+	for _, archivePath := range archivePaths {
+		if err := c.scheduler.Limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		content, err := c.ScrapePage(ctx, c.baseURL+archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch archive %s: %w", archivePath, err)
+		}
+		found := extract.IdeaSlugs(content)
+		c.debugf("Found %d ideas on %s", len(found), archivePath)
+		for _, slug := range found {
+			if !seen[slug] {
+				seen[slug] = true
+				slugs = append(slugs, slug)
+			}
+		}
+	}
+	return slugs, nil
+}
+
+// IdeaDate fetches the overview page of slug and returns the date the idea
+// was published.
+func (c *Client) IdeaDate(ctx context.Context, slug string) (time.Time, error) {
+	if err := c.scheduler.Limiter.Wait(ctx); err != nil {
+		return time.Time{}, err
+	}
+	content, err := c.ScrapePage(ctx, c.baseURL+path.Join("/idea", slug))
+	if err != nil {
+		return time.Time{}, err
+	}
+	_, _, date := extract.IdeaInfo(content)
+	t, err := time.Parse("Jan 2, 2006", date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("no publication date found for %s", slug)
+	}
+	return t, nil
+}
+
+// Page outcome statuses reported in PageOutcome.Status. PageDenied marks a
+// page the account's plan may not see (403), which is not a failure.
+const (
+	PageOK      = "ok"
+	PageRetried = "retried"
+	PageFailed  = "failed"
+	PageDenied  = "denied"
+)
+
+// PageOutcome records how fetching one page of an idea ended.
+type PageOutcome struct {
+	Page     int    `json:"page"`
+	Key      string `json:"key"`
+	URL      string `json:"url"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+	// Access is what a fetched page showed, one of the model.Access
+	// classes; empty for pages that were not fetched.
+	Access string `json:"access,omitempty"`
+}
+
+// ScrapeResult holds the pages scraped for an idea, keyed by PageKey, and the
+// outcome of every page in PageURLs order.
+type ScrapeResult struct {
+	Pages    map[string]string
+	Outcomes []PageOutcome
+}
+
+// Summary returns a one-line count of page outcomes, e.g.
+// "11 pages: 9 ok, 1 retried, 1 failed", followed by the denied pages and
+// the fetched pages that showed a wall when there are any, e.g.
+// "; walls: 1 auth_required".
+func (r *ScrapeResult) Summary() string {
+	counts := make(map[string]int)
+	access := make(map[string]int)
+	for _, o := range r.Outcomes {
+		counts[o.Status]++
+		access[o.Access]++
+	}
+	summary := fmt.Sprintf("%d pages: %d ok, %d retried, %d failed",
+		len(r.Outcomes), counts[PageOK], counts[PageRetried], counts[PageFailed])
+	if n := counts[PageDenied]; n > 0 {
+		summary += fmt.Sprintf(", %d denied", n)
+	}
+	var walls []string
+	for _, class := range []string{model.AccessAuthRequired, model.AccessPlanRestricted, model.AccessNotFound} {
+		if n := access[class]; n > 0 {
+			walls = append(walls, fmt.Sprintf("%d %s", n, class))
+		}
+	}
+	if len(walls) > 0 {
+		summary += "; walls: " + strings.Join(walls, ", ")
+	}
+	return summary
+}
+
+// ScrapePage fetches a single page with the client's session cookies,
+// retrying according to the client's retry policy.
+func (c *Client) ScrapePage(ctx context.Context, url string) (string, error) {
+	resp, _, err := c.fetchPage(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return resp.Body, nil
+}
+
+// fetchPage fetches url with retries and returns the last response, which
+// is non-nil for error pages too, and the number of attempts made.
+func (c *Client) fetchPage(ctx context.Context, url string) (*fetch.Response, int, error) {
+	header := http.Header{
+		"Cache-Control": {"max-age=0"},
+		"Referer":       {c.baseURL + MainPage},
+	}
+
+	var resp *fetch.Response
+	attempts, err := c.retrying(ctx, url, func(string) error {
+		var err error
+		resp, err = c.fetcher.Fetch(ctx, url, header)
+		return err
+	})
+	return resp, attempts, err
+}
+
+// archivePage keeps resp in the client's archive, when it has one, as the
+// page stored under key for slug.
+func (c *Client) archivePage(slug, key string, resp *fetch.Response) {
+	if c.archive == nil {
+		return
+	}
+	_, err := c.archive.Put(storage.ArchiveEntry{
+		Slug:      slug,
+		Page:      key,
+		URL:       resp.URL,
+		FetchedAt: time.Now().UTC(),
+		Status:    resp.StatusCode,
+		Header:    resp.Header,
+	}, []byte(resp.Body))
+	if err != nil {
+		c.logger.Printf("Warning: %v", err)
+	}
+}
+
+// retrying calls do with the current access token until it succeeds,
+// retrying transient failures with backoff and honoring Retry-After. A 401
+// forces one token refresh and a replay of the request regardless of the
+// attempt budget. It returns the number of attempts made; what names the
+// request in log output.
+func (c *Client) retrying(ctx context.Context, what string, do func(token string) error) (int, error) {
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		token := c.accessToken()
+		err := do(token)
+		if err == nil {
+			return attempt, nil
+		}
+
+		var statusErr *fetch.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized && !refreshed {
+			refreshed = true
+			c.debugf("Got 401 for %s, refreshing token and retrying", what)
+			if rerr := c.forceRefresh(ctx, token); rerr != nil {
+				return attempt, fmt.Errorf("%v (token refresh failed: %v)", err, rerr)
+			}
+			continue
+		}
+
+		if !fetch.Retryable(err) || attempt >= c.retry.MaxAttempts {
+			return attempt, err
+		}
+		delay, ok := c.retry.Delay(attempt, err)
+		if !ok {
+			return attempt, fmt.Errorf("%v (Retry-After exceeds %s)", err, c.retry.MaxRetryAfter)
+		}
+		c.debugf("Retrying %s in %s (attempt %d/%d): %v", what, delay.Round(time.Millisecond), attempt+1, c.retry.MaxAttempts, err)
+
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(delay):
+		}
+		if err := c.scheduler.Limiter.Wait(ctx); err != nil {
+			return attempt, err
+		}
+	}
+}
+
+// ScrapeIdea fetches every page in PageURLs(slug, today) and returns their
+// contents keyed by PageKey together with the outcome of each page. Pages are
+// fetched by the client's worker pool under its shared rate limit. Pages that
+// still fail after retrying are logged and left out of Pages. Every response,
+// error pages included, is kept in the client's archive when it has one.
+// Every fetched page is classified with extract.ClassifyPage; a page that
+// shows a sign-in wall triggers a fresh login and is fetched once more.
+func (c *Client) ScrapeIdea(ctx context.Context, slug string, today bool) (*ScrapeResult, error) {
+	pageURLs := PageURLs(slug, today)
+
+	jobs := make([]fetch.Job, len(pageURLs))
+	for i, pagePath := range pageURLs {
+		jobs[i] = fetch.Job{Index: i, URL: c.baseURL + pagePath}
+	}
+
+	c.logger.Printf("Starting to scrape %d pages...", len(pageURLs))
+
+	// Each index is written by exactly one worker
+	attempts := make([]int, len(jobs))
+	access := make([]string, len(jobs))
+	results := c.scheduler.Run(ctx, jobs, func(ctx context.Context, job fetch.Job) (string, error) {
+		c.debugf("[%d/%d] Scraping: %s", job.Index+1, len(pageURLs), pageURLs[job.Index])
+		key := PageKey(slug, pageURLs[job.Index])
+
+		// For protected pages, check if we need to refresh token
+		if job.Index > 0 {
+			if err := c.ensureSession(ctx); err != nil {
+				return "", err
+			}
+		}
+		for relogged := false; ; relogged = true {
+			token := c.accessToken()
+			resp, n, err := c.fetchPage(ctx, job.URL)
+			attempts[job.Index] += n
+			if resp != nil {
+				c.archivePage(slug, key, resp)
+			}
+			if err != nil {
+				return "", err
+			}
+			access[job.Index] = extract.ClassifyPage(key, resp.Body)
+			if access[job.Index] != model.AccessAuthRequired || relogged {
+				return resp.Body, nil
+			}
+			c.logger.Printf("Page %d (%s) shows a sign-in wall, logging in again", job.Index+1, key)
+			if err := c.relogin(ctx, token); err != nil {
+				return "", fmt.Errorf("sign-in wall on %s (login failed: %v)", key, err)
+			}
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store scraped pages for processing
+	result := &ScrapeResult{
+		Pages:    make(map[string]string),
+		Outcomes: make([]PageOutcome, len(results)),
+	}
+
+	for _, res := range results {
+		i := res.Index
+		outcome := PageOutcome{
+			Page:     i + 1,
+			Key:      PageKey(slug, pageURLs[i]),
+			URL:      res.URL,
+			Status:   PageOK,
+			Attempts: attempts[i],
+			Access:   access[i],
+		}
+		var statusErr *fetch.StatusError
+		if errors.As(res.Err, &statusErr) && statusErr.StatusCode == http.StatusForbidden {
+			outcome.Status = PageDenied
+			outcome.Error = res.Err.Error()
+			result.Outcomes[i] = outcome
+			c.logger.Printf("Access denied to page %d (%s) for this account", i+1, outcome.Key)
+			continue
+		}
+		if res.Err != nil {
+			outcome.Status = PageFailed
+			outcome.Error = res.Err.Error()
+			result.Outcomes[i] = outcome
+			c.logger.Printf("Failed to scrape page %d after %d attempts: %v", i+1, attempts[i], res.Err)
+			continue
+		}
+		if attempts[i] > 1 {
+			outcome.Status = PageRetried
+		}
+		result.Outcomes[i] = outcome
+		if outcome.Access != model.AccessOK {
+			c.logger.Printf("Page %d (%s) shows no content: %s", i+1, outcome.Key, outcome.Access)
+		}
+
+		c.debugf("✓ Page %d scraped successfully (%d bytes)", i+1, len(res.Body))
+		if c.htmlDir != "" {
+			htmlFile := filepath.Join(c.htmlDir, fmt.Sprintf("page_%d.html", i+1))
+			if err := os.WriteFile(htmlFile, []byte(res.Body), 0644); err != nil {
+				c.logger.Printf("Warning: failed to save page %d HTML: %v", i+1, err)
+			}
+		}
+
+		result.Pages[outcome.Key] = res.Body
+	}
+
+	return result, nil
+}
+
+// ParseAndSaveData parses all scraped pages and saves the idea as JSON in
+// outputDir, and to the database when the client has one. Pages whose
+// layout drifted from the known-good fingerprints kept in outputDir add a
+// warning to the idea; pages showing a wall are classified in the idea's
+// PageAccess and kept out of the layout check.
+func (c *Client) ParseAndSaveData(ctx context.Context, slug string, pages map[string]string, outputDir string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	idea := extract.Parse(c.site, slug, pages)
+	idea.Warnings = append(idea.Warnings, c.checkLayout(extract.Accessible(pages, idea.PageAccess), outputDir)...)
+	for _, warning := range idea.Warnings {
+		c.logger.Printf("Warning: %s: %s", slug, warning)
+	}
+	return c.SaveIdea(ctx, idea, outputDir)
+}
+
+// SaveIdea saves idea as JSON in outputDir, and to the database when the
+// client has one, filed under today's date. See Store.Save.
+func (c *Client) SaveIdea(ctx context.Context, idea *model.IdeaData, outputDir string) error {
+	return c.store.Save(ctx, idea, outputDir, time.Now())
+}
@@ -0,0 +1,583 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/rubinkazan/ideabrowser-scraper/extract"
+	"github.com/rubinkazan/ideabrowser-scraper/fetch"
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+	"github.com/rubinkazan/ideabrowser-scraper/validate"
+)
+
+// nookPages reads the saved pages of extract/testdata/nook keyed the way
+// ScrapeIdea keys them.
+func nookPages(t *testing.T, slug string) map[string]string {
+	t.Helper()
+	corpus := filepath.Join("extract", "testdata", "nook")
+	pages := make(map[string]string)
+	for i, pagePath := range PageURLs(slug, true) {
+		data, err := os.ReadFile(filepath.Join(corpus, fmt.Sprintf("page_%d.html", i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages[PageKey(slug, pagePath)] = string(data)
+	}
+	return pages
+}
+
+// TestParseAndSaveData parses the saved pages of extract/testdata/nook, keyed
+// the way ScrapeIdea keys them, and compares the JSON file written with the
+// extract package's golden file (regenerate with go test ./extract -update).
+func TestParseAndSaveData(t *testing.T) {
+	const slug = "nook-smart-reading-nooks"
+	pages := nookPages(t, slug)
+
+	logger := log.New(io.Discard, "", 0)
+	c := &Client{logger: logger, store: &Store{Logger: logger}}
+	dir := t.TempDir()
+	if err := c.ParseAndSaveData(context.Background(), slug, pages, dir); err != nil {
+		t.Fatalf("ParseAndSaveData: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "idea_"+slug+"_*.json"))
+	if len(files) != 1 {
+		t.Fatalf("wrote %d JSON files, want 1", len(files))
+	}
+	got, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("extract", "testdata", "golden", "nook_parse.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(append(got, '\n'), want) {
+		t.Errorf("saved JSON differs from golden file:\n%s", got)
+	}
+}
+
+// TestSaveIdeaStrict checks that a strict client still saves an incomplete
+// idea, writes its report next to the JSON and then reports the shortfall.
+func TestSaveIdeaStrict(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	c := &Client{logger: logger, store: &Store{Logger: logger, Strict: true}}
+	dir := t.TempDir()
+	idea := &model.IdeaData{SchemaVersion: model.SchemaVersion, Slug: "sparse", Title: "Sparse"}
+
+	err := c.SaveIdea(context.Background(), idea, dir)
+	var incomplete *IncompleteError
+	if !errors.As(err, &incomplete) {
+		t.Fatalf("SaveIdea = %v, want *IncompleteError", err)
+	}
+	if incomplete.Report.Completeness >= validate.DefaultMinCompleteness {
+		t.Errorf("completeness = %.2f", incomplete.Report.Completeness)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "idea_sparse_*.json"))
+	if len(files) != 1 {
+		t.Fatalf("wrote %d JSON files, want 1", len(files))
+	}
+	report, err := os.ReadFile(storage.ReportPath(files[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "  overview: 1/4 fields, date, description, tags missing\n") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
+
+// TestParseAndSaveDataLayoutDrift saves an idea twice into the same
+// directory, the second time with a renamed ACP section, and checks that
+// the drift is reported and the known-good fingerprint kept.
+func TestParseAndSaveDataLayoutDrift(t *testing.T) {
+	const slug = "nook-smart-reading-nooks"
+	logger := log.New(io.Discard, "", 0)
+	c := &Client{logger: logger, store: &Store{Logger: logger}, layoutTolerance: extract.DefaultLayoutTolerance}
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	pages := nookPages(t, slug)
+	if err := c.ParseAndSaveData(ctx, slug, pages, dir); err != nil {
+		t.Fatal(err)
+	}
+	known, err := storage.LoadFingerprints(dir)
+	if err != nil || len(known) != len(pages) {
+		t.Fatalf("stored %d fingerprints (%v), want %d", len(known), err, len(pages))
+	}
+
+	pages["acp"] = strings.Replace(pages["acp"], "AUDIENCE ANALYSIS", "AUDIENCE INSIGHTS", 1)
+	if err := c.ParseAndSaveData(ctx, slug, pages, dir); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "idea_"+slug+"_*.json"))
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `layout drift on acp: `) || !strings.Contains(string(data), `\"AUDIENCE ANALYSIS\"`) {
+		t.Errorf("no drift warning naming the anchor in:\n%s", data)
+	}
+
+	after, err := storage.LoadFingerprints(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(after["acp"], known["acp"]) {
+		t.Error("drifted page replaced its known-good fingerprint")
+	}
+}
+
+// TestCheckLayoutGradualDrift changes a page a little more on every run,
+// each time by less than the tolerance, and checks that the changes add up
+// to drift against the first run's fingerprint.
+func TestCheckLayoutGradualDrift(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	c := &Client{logger: logger, layoutTolerance: extract.DefaultLayoutTolerance}
+	dir := t.TempDir()
+
+	// page returns a page of 20 distinct elements, the first changed of
+	// them renamed
+	page := func(changed int) string {
+		var b strings.Builder
+		b.WriteString("<html><body><div>")
+		for i := 0; i < 20; i++ {
+			tag := fmt.Sprintf("x-old%d", i)
+			if i < changed {
+				tag = fmt.Sprintf("x-new%d", i)
+			}
+			fmt.Fprintf(&b, "<%s>text</%s>", tag, tag)
+		}
+		b.WriteString("</div></body></html>")
+		return b.String()
+	}
+
+	var drifted int
+	for run := 0; run <= 6; run++ {
+		warnings := c.checkLayout(map[string]string{"why-now": page(run)}, dir)
+		if len(warnings) > 0 && drifted == 0 {
+			drifted = run
+		}
+	}
+	if drifted == 0 {
+		t.Fatal("six small changes in a row never reported drift")
+	}
+	if drifted == 1 {
+		t.Error("a single changed element reported drift")
+	}
+
+	// Accepting the layout makes the current page the baseline
+	c.acceptLayout = true
+	c.checkLayout(map[string]string{"why-now": page(6)}, dir)
+	c.acceptLayout = false
+	if warnings := c.checkLayout(map[string]string{"why-now": page(7)}, dir); len(warnings) > 0 {
+		t.Errorf("drift after accepting the layout: %v", warnings)
+	}
+}
+
+// TestScrapeIdeaArchive checks that every response of a scrape, error pages
+// included, is kept in the archive under its page key.
+func TestScrapeIdeaArchive(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"access_token":"token","refresh_token":"refresh","expires_in":3600}`)
+	})
+	mux.HandleFunc("/idea/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/market-gap") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprintf(w, "<html><h1>%s</h1></html>", r.URL.Path)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	archive, err := storage.OpenArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(Config{
+		AnonKey:    "anon",
+		ProjectURL: srv.URL,
+		Email:      "user@example.com",
+		Password:   "secret",
+		BaseURL:    srv.URL,
+		Logger:     log.New(io.Discard, "", 0),
+		RateLimit:  rate.Inf,
+		Retry:      fetch.RetryPolicy{MaxAttempts: 1},
+		Archive:    archive,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.ScrapeIdea(ctx, "nook", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := archive.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(result.Outcomes) {
+		t.Fatalf("archived %d pages, want %d", len(entries), len(result.Outcomes))
+	}
+
+	byPage := make(map[string]storage.ArchiveEntry)
+	for _, e := range entries {
+		byPage[e.Page] = e
+	}
+	if e := byPage["market-gap"]; e.Status != http.StatusNotFound || e.Slug != "nook" {
+		t.Errorf("market-gap entry = %+v", e)
+	}
+	e := byPage["acp"]
+	if e.Status != http.StatusOK || e.URL != srv.URL+"/idea/nook/acp" || e.Header.Get("Set-Cookie") != "" {
+		t.Errorf("acp entry = %+v", e)
+	}
+	body, err := archive.Open(e.SHA256)
+	if err != nil || string(body) != result.Pages["acp"] {
+		t.Errorf("archived acp body = %q, %v", body, err)
+	}
+}
+
+func TestDiscoverIdeaSlugs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"access_token":"token","refresh_token":"refresh","expires_in":3600}`)
+	})
+	mux.HandleFunc("/database", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<a href="/idea/second">2</a><a href="/idea/first">1</a><a href="/idea/second/acp">2</a>`)
+	})
+	mux.HandleFunc("/database/page-2", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `<a href="/idea/first">1</a><a href="/idea/third?ref=list">3</a>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{
+		AnonKey:    "anon",
+		ProjectURL: srv.URL,
+		Email:      "user@example.com",
+		Password:   "secret",
+		BaseURL:    srv.URL,
+		Logger:     log.New(io.Discard, "", 0),
+		RateLimit:  rate.Inf,
+		Retry:      fetch.RetryPolicy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.DiscoverIdeaSlugs(ctx, []string{"/database", "/database/page-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"second", "first", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverIdeaSlugs = %q, want %q", got, want)
+	}
+	// DefaultArchivePaths when none are given
+	if got, err := c.DiscoverIdeaSlugs(ctx, nil); err != nil || len(got) != 2 {
+		t.Errorf("DiscoverIdeaSlugs(nil) = %q, %v", got, err)
+	}
+	if _, err := c.DiscoverIdeaSlugs(ctx, []string{"/missing"}); err == nil {
+		t.Error("DiscoverIdeaSlugs of a missing listing page succeeded")
+	}
+}
+
+// TestScrapeIdeaHTMLDir checks pages are copied to Config.HTMLDir, and that
+// a failed copy is logged without failing the scrape.
+func TestScrapeIdeaHTMLDir(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"access_token":"token","refresh_token":"refresh","expires_in":3600}`)
+	})
+	mux.HandleFunc("/idea/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html><h1>%s</h1></html>", r.URL.Path)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, htmlDir := range []string{t.TempDir(), notDir} {
+		var logs bytes.Buffer
+		c, err := NewClient(Config{
+			AnonKey:    "anon",
+			ProjectURL: srv.URL,
+			Email:      "user@example.com",
+			Password:   "secret",
+			BaseURL:    srv.URL,
+			Logger:     log.New(&logs, "", 0),
+			RateLimit:  rate.Inf,
+			Retry:      fetch.RetryPolicy{MaxAttempts: 1},
+			HTMLDir:    htmlDir,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.Background()
+		if err := c.Authenticate(ctx); err != nil {
+			t.Fatal(err)
+		}
+		result, err := c.ScrapeIdea(ctx, "nook", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		saved, _ := os.ReadFile(filepath.Join(htmlDir, "page_2.html"))
+		warned := strings.Contains(logs.String(), "Warning: failed to save page 2 HTML")
+		if htmlDir == notDir {
+			if !warned || len(result.Pages) != len(result.Outcomes) {
+				t.Errorf("unwritable HTML dir: warned %v, %d pages; logs:\n%s", warned, len(result.Pages), logs.String())
+			}
+		} else if warned || string(saved) != result.Pages["acp"] {
+			t.Errorf("page_2.html = %q, want the acp page; logs:\n%s", saved, logs.String())
+		}
+	}
+}
+
+// TestScrapeIdeaDenied checks that a page the account may not see is
+// recorded as denied rather than failed.
+func TestScrapeIdeaDenied(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"access_token":"token","refresh_token":"refresh","expires_in":3600}`)
+	})
+	mux.HandleFunc("/idea/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/acp") {
+			http.Error(w, "upgrade to see this page", http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, "<html><h1>%s</h1></html>", r.URL.Path)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{
+		AnonKey:    "anon",
+		ProjectURL: srv.URL,
+		Email:      "user@example.com",
+		Password:   "secret",
+		BaseURL:    srv.URL,
+		Logger:     log.New(io.Discard, "", 0),
+		RateLimit:  rate.Inf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.ScrapeIdea(ctx, "nook", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range result.Outcomes {
+		if want := o.Key == "acp"; (o.Status == PageDenied) != want || o.Status == PageFailed {
+			t.Errorf("%s outcome = %+v", o.Key, o)
+		}
+	}
+	if _, ok := result.Pages["acp"]; ok {
+		t.Error("denied page kept in Pages")
+	}
+	if want := fmt.Sprintf("%d pages: %d ok, 0 retried, 0 failed, 1 denied", len(result.Outcomes), len(result.Outcomes)-1); result.Summary() != want {
+		t.Errorf("Summary = %q, want %q", result.Summary(), want)
+	}
+}
+
+// TestScrapeIdeaWalls checks that pages answering 200 with a wall are
+// classified, and that a sign-in wall triggers one login and a refetch.
+func TestScrapeIdeaWalls(t *testing.T) {
+	var logins atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		n := logins.Add(1)
+		fmt.Fprintf(w, `{"access_token":"token%d","refresh_token":"refresh","expires_in":3600}`, n)
+	})
+	mux.HandleFunc("/idea/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/acp") && logins.Load() < 2:
+			io.WriteString(w, `<html><body><main><p>Please sign in to continue.</p></main></body></html>`)
+		case strings.HasSuffix(r.URL.Path, "/acp"):
+			io.WriteString(w, `<html><body><h1>ACP Framework Analysis</h1></body></html>`)
+		case strings.HasSuffix(r.URL.Path, "/value-ladder"):
+			io.WriteString(w, `<html><body><main><p>Upgrade to Pro to see the value ladder.</p></main></body></html>`)
+		case strings.HasSuffix(r.URL.Path, "/why-now"):
+			io.WriteString(w, `<html><head><title>Page not found</title></head><body></body></html>`)
+		default:
+			fmt.Fprintf(w, "<html><h1>%s</h1></html>", r.URL.Path)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{
+		AnonKey:    "anon",
+		ProjectURL: srv.URL,
+		Email:      "user@example.com",
+		Password:   "secret",
+		BaseURL:    srv.URL,
+		Logger:     log.New(io.Discard, "", 0),
+		RateLimit:  rate.Inf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.ScrapeIdea(ctx, "nook", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := logins.Load(); n != 2 {
+		t.Errorf("logged in %d times, want 2", n)
+	}
+	want := map[string]string{
+		"acp":          model.AccessOK,
+		"value-ladder": model.AccessPlanRestricted,
+		"why-now":      model.AccessNotFound,
+	}
+	for _, o := range result.Outcomes {
+		class, ok := want[o.Key]
+		if !ok {
+			class = model.AccessOK
+		}
+		if o.Access != class {
+			t.Errorf("%s access = %q, want %q", o.Key, o.Access, class)
+		}
+	}
+	if o := result.Outcomes[1]; o.Key != "acp" || o.Status != PageRetried || o.Attempts != 2 {
+		t.Errorf("acp outcome = %+v", o)
+	}
+	if !strings.HasSuffix(result.Summary(), "; walls: 1 plan_restricted, 1 not_found") {
+		t.Errorf("Summary = %q", result.Summary())
+	}
+
+	idea := extract.Parse(c.site, "nook", result.Pages)
+	if idea.PageAccess["value-ladder"] != model.AccessPlanRestricted || idea.PageAccess["acp"] != model.AccessOK {
+		t.Errorf("PageAccess = %v", idea.PageAccess)
+	}
+}
+
+// TestFetchPageRetries checks the attempts made for each kind of failure
+// and what the page ends with.
+func TestFetchPageRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []int // answered in turn, then 200
+		header   http.Header
+		attempts int
+		grants   int32 // token requests after the first login
+		fails    bool
+		wait     time.Duration
+	}{
+		{name: "429 with Retry-After", statuses: []int{429}, header: http.Header{"Retry-After": {"1"}}, attempts: 2, wait: time.Second},
+		{name: "503 twice", statuses: []int{503, 503}, attempts: 3},
+		{name: "401 refreshes and replays", statuses: []int{401}, attempts: 2, grants: 1},
+		{name: "401 refreshes once", statuses: []int{401, 401}, attempts: 2, grants: 1, fails: true},
+		{name: "500 until out of attempts", statuses: []int{500, 500, 500, 500, 500}, attempts: 4, fails: true},
+		{name: "Retry-After beyond MaxRetryAfter", statuses: []int{429}, header: http.Header{"Retry-After": {"3600"}}, attempts: 1, fails: true},
+		{name: "404 is not retried", statuses: []int{404}, attempts: 1, fails: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var grants atomic.Int32
+			var requests atomic.Int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/auth/v1/token", func(w http.ResponseWriter, r *http.Request) {
+				n := grants.Add(1)
+				fmt.Fprintf(w, `{"access_token":"token%d","refresh_token":"refresh","expires_in":3600}`, n)
+			})
+			mux.HandleFunc("/idea/", func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1))
+				if n <= len(tt.statuses) {
+					for k, v := range tt.header {
+						w.Header()[k] = v
+					}
+					w.WriteHeader(tt.statuses[n-1])
+					return
+				}
+				io.WriteString(w, "<html>ok</html>")
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			c, err := NewClient(Config{
+				AnonKey:    "anon",
+				ProjectURL: srv.URL,
+				Email:      "user@example.com",
+				Password:   "secret",
+				BaseURL:    srv.URL,
+				Logger:     log.New(io.Discard, "", 0),
+				RateLimit:  rate.Inf,
+				Retry: fetch.RetryPolicy{
+					MaxAttempts:   4,
+					BaseDelay:     time.Millisecond,
+					MaxDelay:      4 * time.Millisecond,
+					MaxRetryAfter: time.Minute,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := c.Authenticate(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			resp, attempts, err := c.fetchPage(ctx, srv.URL+"/idea/nook/acp")
+			if elapsed := time.Since(start); elapsed < tt.wait {
+				t.Errorf("retried after %s, want at least %s", elapsed, tt.wait)
+			}
+			if attempts != tt.attempts || int(requests.Load()) != tt.attempts {
+				t.Errorf("attempts = %d with %d requests, want %d", attempts, requests.Load(), tt.attempts)
+			}
+			if n := grants.Load() - 1; n != tt.grants {
+				t.Errorf("made %d token requests, want %d", n, tt.grants)
+			}
+			if tt.fails {
+				if err == nil {
+					t.Errorf("fetchPage succeeded with %q", resp.Body)
+				}
+				return
+			}
+			if err != nil || resp.Body != "<html>ok</html>" {
+				t.Errorf("fetchPage = %+v, %v", resp, err)
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 PROJECT_DIR="$(dirname "$SCRIPT_DIR")"
 SCRAPER_BIN="$PROJECT_DIR/ideabrowser-scraper"
 JSON_DIR="$PROJECT_DIR/data/json"
+ARCHIVE_DIR="$PROJECT_DIR/data/archive"
+DB_PATH="${DB_PATH:-$PROJECT_DIR/data/ideas.db}"
+MIN_COMPLETENESS="${MIN_COMPLETENESS:-0.7}"
 LOG_DIR="$PROJECT_DIR/data/logs"
 LOG_FILE="$LOG_DIR/scraper-$(date +%Y-%m).log"
 
@@ -32,40 +35,21 @@ cd "$PROJECT_DIR" || exit 1
 if [ ! -f "$SCRAPER_BIN" ]; then
     log "ERROR: Scraper binary not found at $SCRAPER_BIN"
     log "Building scraper..."
-    go build -o "$SCRAPER_BIN" scraper.go
+    go build -o "$SCRAPER_BIN" ./cmd/ideabrowser-scraper
     if [ $? -ne 0 ]; then
         log "ERROR: Failed to build scraper"
         exit 1
     fi
 fi
 
-# Run the scraper
+# Run the scraper (stores the idea in SQLite as part of the run). -strict
+# fails the run when the idea is saved with too many fields missing.
 log "Running scraper..."
-"$SCRAPER_BIN" -output "$JSON_DIR" -verbose 2>&1 | tee -a "$LOG_FILE"
+"$SCRAPER_BIN" -output "$JSON_DIR" -db "$DB_PATH" -archive "$ARCHIVE_DIR" -strict -min-completeness "$MIN_COMPLETENESS" -verbose 2>&1 | tee -a "$LOG_FILE"
 SCRAPER_EXIT_CODE=${PIPESTATUS[0]}
 
 if [ $SCRAPER_EXIT_CODE -eq 0 ]; then
     log "Scraper completed successfully"
-    
-    # Find the JSON file created today
-    TODAY_JSON=$(find "$JSON_DIR" -name "idea_*_$(date +%Y-%m-%d).json" -type f -mtime -1 | head -1)
-    
-    if [ -n "$TODAY_JSON" ]; then
-        log "Found today's JSON: $(basename "$TODAY_JSON")"
-        
-        # Import to SQLite
-        log "Importing to SQLite database..."
-        "$SCRIPT_DIR/ingest.sh" "$TODAY_JSON" 2>&1 | tee -a "$LOG_FILE"
-        
-        if [ ${PIPESTATUS[0]} -eq 0 ]; then
-            log "Import completed successfully"
-        else
-            log "ERROR: Import to SQLite failed"
-            exit 1
-        fi
-    else
-        log "WARNING: No JSON file found for today"
-    fi
 else
     log "ERROR: Scraper failed with exit code $SCRAPER_EXIT_CODE"
     exit 1
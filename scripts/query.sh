@@ -99,7 +99,9 @@ EOF
             sqlite3 "$DB_PATH" -csv -header <<EOF > "$output_file"
 SELECT slug, scrape_date, title, description, tags,
        value_equation_score, acp_audience_score, 
-       acp_community_score, acp_product_score, market_position
+       acp_community_score, acp_product_score, market_position,
+       value_dream_outcome, value_perceived_likelihood,
+       value_time_delay, value_effort_sacrifice
 FROM ideas 
 ORDER BY scrape_date DESC;
 EOF
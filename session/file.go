@@ -0,0 +1,40 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// File stores the session as plain JSON in a file only its owner can read.
+type File struct {
+	Path string
+}
+
+// Load implements Store.
+func (f *File) Load() (*Session, error) {
+	data, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %v", f.Path, err)
+	}
+	return &s, nil
+}
+
+// Save implements Store.
+func (f *File) Save(s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(f.Path, data); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	return nil
+}
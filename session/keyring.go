@@ -0,0 +1,52 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Keyring stores the session as a secret of the desktop keyring: the
+// freedesktop Secret Service over D-Bus on Linux, the Keychain on macOS.
+type Keyring struct {
+	Service string
+	User    string
+}
+
+// KeyringAvailable reports whether a keyring answers lookups of the item
+// service and user, as it does not on headless servers without a D-Bus
+// session.
+func KeyringAvailable(service, user string) bool {
+	_, err := keyring.Get(service, user)
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// Load implements Store.
+func (k *Keyring) Load() (*Session, error) {
+	secret, err := keyring.Get(k.Service, k.User)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session from keyring: %v", err)
+	}
+	var s Session
+	if err := json.Unmarshal([]byte(secret), &s); err != nil {
+		return nil, fmt.Errorf("invalid session in keyring: %v", err)
+	}
+	return &s, nil
+}
+
+// Save implements Store.
+func (k *Keyring) Save(s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := keyring.Set(k.Service, k.User, string(data)); err != nil {
+		return fmt.Errorf("failed to save session to keyring: %v", err)
+	}
+	return nil
+}
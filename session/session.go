@@ -0,0 +1,115 @@
+// Package session keeps a Supabase session between runs, so a run within
+// the access token's lifetime needs no token request at all. Stores keep the
+// session in an age-encrypted file, the desktop Secret Service or a plain
+// file readable only by its owner.
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Session is the part of a Supabase session kept between runs.
+type Session struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresAt is the Unix time at which AccessToken expires.
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// Valid reports whether the access token can still be used at now.
+func (s *Session) Valid(now time.Time) bool {
+	return s != nil && s.AccessToken != "" && now.Unix() < s.ExpiresAt
+}
+
+// Store persists a Session.
+type Store interface {
+	// Load returns the stored session, or nil when none was saved.
+	Load() (*Session, error)
+	// Save replaces the stored session with s.
+	Save(s *Session) error
+}
+
+// Kinds of store accepted by Open.
+const (
+	KindAuto    = "auto"
+	KindAge     = "age"
+	KindKeyring = "keyring"
+	KindFile    = "file"
+)
+
+// PassphraseEnv is the environment variable the CLI reads the passphrase
+// of an age-encrypted store from.
+const PassphraseEnv = "IDEABROWSER_SESSION_PASSPHRASE"
+
+// Options locate the stores Open chooses between.
+type Options struct {
+	// Dir holds the session file of the age and file stores.
+	Dir string
+	// Passphrase encrypts the age store.
+	Passphrase string
+	// Service and User name the Secret Service item, e.g.
+	// "ideabrowser-scraper" and the account's email.
+	Service string
+	User    string
+}
+
+// Open returns the store of the given kind. KindAuto picks the age store
+// when a passphrase is set, else the Secret Service when one is running,
+// else the plain file.
+func Open(kind string, opts Options) (Store, error) {
+	if kind == KindAuto {
+		switch {
+		case opts.Passphrase != "":
+			kind = KindAge
+		case KeyringAvailable(opts.Service, opts.User):
+			kind = KindKeyring
+		default:
+			kind = KindFile
+		}
+	}
+
+	switch kind {
+	case KindAge:
+		if opts.Passphrase == "" {
+			return nil, fmt.Errorf("the age session store needs a passphrase in %s", PassphraseEnv)
+		}
+		return &AgeFile{Path: filepath.Join(opts.Dir, "session.age"), Passphrase: opts.Passphrase}, nil
+	case KindKeyring:
+		if !KeyringAvailable(opts.Service, opts.User) {
+			return nil, errors.New("no Secret Service is available")
+		}
+		return &Keyring{Service: opts.Service, User: opts.User}, nil
+	case KindFile:
+		return &File{Path: filepath.Join(opts.Dir, "session.json")}, nil
+	}
+	return nil, fmt.Errorf("unknown session store %q (want auto, age, keyring or file)", kind)
+}
+
+// writeFile replaces path with data, readable only by its owner. The data
+// is written under a temporary name and renamed, so an interrupted write
+// never leaves a truncated session behind.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// CreateTemp creates the file with mode 0600
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+var testSession = &Session{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: 1737093600}
+
+// roundTrip checks that store starts empty and returns what it saved.
+func roundTrip(t *testing.T, store Store) {
+	t.Helper()
+	if s, err := store.Load(); s != nil || err != nil {
+		t.Fatalf("empty store Load = %+v, %v", s, err)
+	}
+	if err := store.Save(testSession); err != nil {
+		t.Fatal(err)
+	}
+	s, err := store.Load()
+	if err != nil || !reflect.DeepEqual(s, testSession) {
+		t.Fatalf("Load = %+v, %v", s, err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	f := &File{Path: filepath.Join(t.TempDir(), "session.json")}
+	roundTrip(t, f)
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("session file mode = %o, want 600", perm)
+	}
+}
+
+func TestAgeFile(t *testing.T) {
+	f := &AgeFile{Path: filepath.Join(t.TempDir(), "session.age"), Passphrase: "correct horse", WorkFactor: 10}
+	roundTrip(t, f)
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("refresh")) {
+		t.Error("session file holds the refresh token in plaintext")
+	}
+	wrong := &AgeFile{Path: f.Path, Passphrase: "wrong"}
+	if _, err := wrong.Load(); err == nil {
+		t.Error("Load succeeded with the wrong passphrase")
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	keyring.MockInit()
+	roundTrip(t, &Keyring{Service: "ideabrowser-scraper", User: "user@example.com"})
+}
+
+func TestOpen(t *testing.T) {
+	keyring.MockInit()
+	dir := t.TempDir()
+	tests := []struct {
+		kind string
+		opts Options
+		want Store
+	}{
+		{KindAuto, Options{Dir: dir, Passphrase: "pw"}, &AgeFile{Path: filepath.Join(dir, "session.age"), Passphrase: "pw"}},
+		{KindAuto, Options{Dir: dir, Service: "s", User: "u"}, &Keyring{Service: "s", User: "u"}},
+		{KindFile, Options{Dir: dir, Passphrase: "pw"}, &File{Path: filepath.Join(dir, "session.json")}},
+	}
+	for _, tt := range tests {
+		got, err := Open(tt.kind, tt.opts)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Open(%q, %+v) = %#v, %v", tt.kind, tt.opts, got, err)
+		}
+	}
+	if _, err := Open(KindAge, Options{Dir: dir}); err == nil {
+		t.Error("age store opened without a passphrase")
+	}
+}
+
+func TestValid(t *testing.T) {
+	now := time.Unix(testSession.ExpiresAt, 0)
+	if !testSession.Valid(now.Add(-time.Minute)) || testSession.Valid(now) {
+		t.Error("Valid disagrees with ExpiresAt")
+	}
+	var none *Session
+	if none.Valid(now) {
+		t.Error("nil session is valid")
+	}
+}
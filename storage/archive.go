@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ManifestFile is the name of an archive's manifest, one JSON ArchiveEntry
+// per line in the order pages were archived.
+const ManifestFile = "manifest.jsonl"
+
+// archivedHeaders are the response headers kept in the manifest. Others,
+// Set-Cookie among them, are dropped so the archive holds no session
+// secrets.
+var archivedHeaders = []string{
+	"Cache-Control", "Content-Language", "Content-Type", "Date", "Etag",
+	"Last-Modified", "Retry-After", "Server", "Vary", "X-Matched-Path",
+	"X-Nextjs-Cache", "X-Powered-By", "X-Vercel-Cache", "X-Vercel-Id",
+}
+
+// ArchiveEntry describes one fetched page in the archive's manifest.
+type ArchiveEntry struct {
+	Slug string `json:"slug"`
+	// Page is the key the page is parsed under, e.g. "acp".
+	Page      string      `json:"page"`
+	URL       string      `json:"url"`
+	FetchedAt time.Time   `json:"fetched_at"`
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header,omitempty"`
+	// SHA256 is the hex digest of the uncompressed body and names its blob.
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// Archive is a permanent, content-addressed store of fetched pages. Each
+// distinct body is kept once, gzip-compressed, under blobs/ab/<sha256>.gz;
+// the manifest records every fetch that produced it. Archive is safe for
+// concurrent use.
+type Archive struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// OpenArchive opens the archive in dir, creating it if needed.
+func OpenArchive(dir string) (*Archive, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "blobs"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive: %v", err)
+	}
+	return &Archive{dir: dir}, nil
+}
+
+// Dir returns the directory of the archive.
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// Put stores body and appends entry to the manifest, filling in its digest
+// and size. A body already in the archive is not written again.
+func (a *Archive) Put(entry ArchiveEntry, body []byte) (ArchiveEntry, error) {
+	sum := sha256.Sum256(body)
+	entry.SHA256 = hex.EncodeToString(sum[:])
+	entry.Size = len(body)
+	entry.Header = keptHeaders(entry.Header)
+
+	if err := a.writeBlob(entry.SHA256, body); err != nil {
+		return entry, fmt.Errorf("failed to archive %s: %v", entry.URL, err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, err := os.OpenFile(filepath.Join(a.dir, ManifestFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return entry, err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return entry, fmt.Errorf("failed to write manifest: %v", err)
+	}
+	return entry, f.Close()
+}
+
+// blobPath returns the path of the blob with the given digest.
+func (a *Archive) blobPath(digest string) string {
+	return filepath.Join(a.dir, "blobs", digest[:2], digest+".gz")
+}
+
+func (a *Archive) writeBlob(digest string, body []byte) error {
+	path := a.blobPath(digest)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	// Write under a unique name and rename, so a blob is either complete or
+	// absent even when two workers archive the same body at once
+	tmp, err := os.CreateTemp(filepath.Dir(path), digest+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open returns the body stored under digest, checking it against the
+// digest.
+func (a *Archive) Open(digest string) ([]byte, error) {
+	if len(digest) != sha256.Size*2 {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+	f, err := os.Open(a.blobPath(digest))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("blob %s: %v", digest, err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("blob %s: %v", digest, err)
+	}
+	if sum := sha256.Sum256(body); hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("blob %s is corrupt", digest)
+	}
+	return body, nil
+}
+
+// Entries returns every manifest entry in the order pages were archived.
+func (a *Archive) Entries() ([]ArchiveEntry, error) {
+	f, err := os.Open(filepath.Join(a.dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []ArchiveEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e ArchiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", ManifestFile, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// keptHeaders returns the archivedHeaders present in h.
+func keptHeaders(h http.Header) http.Header {
+	kept := make(http.Header)
+	for _, name := range archivedHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			kept[name] = v
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	a, err := OpenArchive(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte("<html><h1>Nook</h1></html>")
+	fetched := time.Date(2025, 1, 17, 6, 0, 0, 0, time.UTC)
+	header := http.Header{"Content-Type": {"text/html"}, "Set-Cookie": {"sb-access-token=secret"}}
+
+	first, err := a.Put(ArchiveEntry{Slug: "nook", Page: "acp", URL: "https://www.ideabrowser.com/idea/nook/acp", FetchedAt: fetched, Status: 200, Header: header}, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fetching the same content again adds an entry but no blob
+	second, err := a.Put(ArchiveEntry{Slug: "nook", Page: "acp", URL: first.URL, FetchedAt: fetched.Add(24 * time.Hour), Status: 200}, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.SHA256 != second.SHA256 || first.Size != len(body) {
+		t.Errorf("entries %+v and %+v", first, second)
+	}
+	blobs, _ := filepath.Glob(filepath.Join(a.Dir(), "blobs", "*", "*.gz"))
+	if len(blobs) != 1 {
+		t.Errorf("stored %d blobs, want 1", len(blobs))
+	}
+
+	entries, err := a.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || !entries[0].FetchedAt.Equal(fetched) || entries[1].Page != "acp" {
+		t.Fatalf("manifest = %+v", entries)
+	}
+	if entries[0].Header.Get("Content-Type") != "text/html" || entries[0].Header.Get("Set-Cookie") != "" {
+		t.Errorf("archived headers = %v", entries[0].Header)
+	}
+
+	got, err := a.Open(entries[0].SHA256)
+	if err != nil || string(got) != string(body) {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+
+	// A blob that no longer matches its digest is refused
+	if err := os.WriteFile(blobs[0], []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Open(first.SHA256); err == nil {
+		t.Error("Open accepted a corrupt blob")
+	}
+}
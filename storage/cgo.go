@@ -0,0 +1,7 @@
+//go:build cgo
+
+package storage
+
+// haveCgo reports whether the binary was built with cgo, which the SQLite
+// driver needs.
+const haveCgo = true
@@ -0,0 +1,73 @@
+// Package storage persists scraped ideas.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// JSONFilename returns the name of the JSON file an idea scraped on day is
+// written to.
+func JSONFilename(slug string, day time.Time) string {
+	return fmt.Sprintf("idea_%s_%s.json", slug, day.Format("2006-01-02"))
+}
+
+// HasJSON reports whether dir already holds a JSON file for slug from any day.
+func HasJSON(dir, slug string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "idea_"+slug+"_????-??-??.json"))
+	return len(matches) > 0
+}
+
+// FindJSON returns the JSON file in dir for slug scraped on day or, when
+// there is none, the latest one from any day. It returns "" when dir holds
+// no JSON file for slug.
+func FindJSON(dir, slug string, day time.Time) string {
+	exact := filepath.Join(dir, JSONFilename(slug, day))
+	if _, err := os.Stat(exact); err == nil {
+		return exact
+	}
+	// Dates in the names sort in time order
+	matches, _ := filepath.Glob(filepath.Join(dir, "idea_"+slug+"_????-??-??.json"))
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1]
+}
+
+// WriteJSON saves idea as indented JSON in dir and returns the file path.
+func WriteJSON(dir string, idea *model.IdeaData, day time.Time) (string, error) {
+	jsonData, err := json.MarshalIndent(idea, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	filePath := filepath.Join(dir, JSONFilename(idea.Slug, day))
+	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+		return "", fmt.Errorf("failed to write JSON file: %v", err)
+	}
+	return filePath, nil
+}
+
+// ReportPath returns the path of the completeness report written next to
+// the JSON file at jsonPath.
+func ReportPath(jsonPath string) string {
+	return strings.TrimSuffix(jsonPath, ".json") + ".report.txt"
+}
+
+// WriteReport saves report next to the JSON file at jsonPath and returns
+// the report's path.
+func WriteReport(jsonPath, report string) (string, error) {
+	reportPath := ReportPath(jsonPath)
+	if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %v", err)
+	}
+	return reportPath, nil
+}
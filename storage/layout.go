@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// FingerprintsFile is where the known-good page fingerprints are kept,
+// relative to the JSON output directory. It lives in its own directory so
+// that imports of *.json in the output directory do not pick it up.
+const FingerprintsFile = "layout/fingerprints.json"
+
+// LoadFingerprints reads the known-good page fingerprints kept in dir, by
+// page key. A missing file yields an empty map.
+func LoadFingerprints(dir string) (map[string]*model.Fingerprint, error) {
+	fingerprints := make(map[string]*model.Fingerprint)
+	data, err := os.ReadFile(filepath.Join(dir, FingerprintsFile))
+	if os.IsNotExist(err) {
+		return fingerprints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fingerprints); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", FingerprintsFile, err)
+	}
+	return fingerprints, nil
+}
+
+// SaveFingerprints replaces the known-good page fingerprints kept in dir.
+func SaveFingerprints(dir string, fingerprints map[string]*model.Fingerprint) error {
+	data, err := json.MarshalIndent(fingerprints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fingerprints: %v", err)
+	}
+	path := filepath.Join(dir, FingerprintsFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// Write a temporary file first so an interrupted run cannot leave a
+	// truncated known-good set behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write fingerprints: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,9 @@
+-- Value equation component scores, from framework_fit.value_equation.components.
+-- Rows saved before this migration keep NULL until re-ingested.
+
+ALTER TABLE ideas ADD COLUMN value_dream_outcome INTEGER;
+ALTER TABLE ideas ADD COLUMN value_perceived_likelihood INTEGER;
+ALTER TABLE ideas ADD COLUMN value_time_delay INTEGER;
+ALTER TABLE ideas ADD COLUMN value_effort_sacrifice INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_value_components ON ideas(value_dream_outcome, value_perceived_likelihood, value_time_delay, value_effort_sacrifice);
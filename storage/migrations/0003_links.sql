@@ -0,0 +1,15 @@
+-- Outbound links cited by each idea, from the JSON "links" list.
+-- Rows saved before this migration have none until re-ingested.
+
+CREATE TABLE IF NOT EXISTS links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    idea_slug TEXT NOT NULL REFERENCES ideas(slug) ON DELETE CASCADE,
+    page TEXT NOT NULL, -- Page key the link is on, e.g. why-now
+    url TEXT NOT NULL,  -- Absolute, without tracking parameters
+    host TEXT NOT NULL,
+    anchor_text TEXT,
+    UNIQUE(idea_slug, page, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_links_url ON links(url);
+CREATE INDEX IF NOT EXISTS idx_links_host ON links(host);
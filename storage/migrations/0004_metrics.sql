@@ -0,0 +1,17 @@
+-- Figures quoted on the detail pages, from the JSON "metrics" list.
+-- Rows saved before this migration have none until re-ingested.
+
+CREATE TABLE IF NOT EXISTS metrics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    idea_slug TEXT NOT NULL REFERENCES ideas(slug) ON DELETE CASCADE,
+    page TEXT,           -- Page key the figure is on, e.g. market-gap
+    label TEXT NOT NULL, -- Heading or label it is under, e.g. Market Size
+    text TEXT NOT NULL,  -- As written, e.g. $2.3B
+    value REAL NOT NULL, -- Lower bound of a range
+    max_value REAL,      -- Upper bound of a range
+    unit TEXT NOT NULL,  -- Currency code, count, percent, hours, days, weeks, months or years
+    period TEXT          -- hour, day, week, month or year for rates
+);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_label ON metrics(label, unit, value);
+CREATE INDEX IF NOT EXISTS idx_metrics_slug ON metrics(idea_slug);
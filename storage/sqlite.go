@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// SQLite stores ideas in a SQLite database using the schema built by the
+// embedded migrations. The driver, github.com/mattn/go-sqlite3, is a cgo
+// package: binaries built with CGO_ENABLED=0 cannot open a database.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (creating if needed) the database at path and applies any
+// pending migrations.
+func OpenSQLite(ctx context.Context, path string) (*SQLite, error) {
+	if !haveCgo {
+		return nil, fmt.Errorf("failed to open database: SQLite support needs a cgo build (CGO_ENABLED=1 and a C compiler)")
+	}
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+	s := &SQLite{db: db}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database.
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies the embedded migrations newer than the database's
+// user_version, in file name order, each in its own transaction.
+func (s *SQLite) migrate(ctx context.Context) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %v", err)
+	}
+
+	for i, name := range names {
+		version := i + 1
+		if version <= current {
+			continue
+		}
+		script, err := migrations.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s failed: %v", path.Base(name), err)
+		}
+		// PRAGMA does not accept bound parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %s failed: %v", path.Base(name), err)
+		}
+	}
+	return nil
+}
+
+// ScrapeDate returns the ISO date an idea is filed under: its published date
+// ("Jan 17, 2025") when it parses, otherwise fallback.
+func ScrapeDate(idea *model.IdeaData, fallback time.Time) string {
+	if t, err := time.Parse("Jan 2, 2006", idea.Date); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return fallback.Format("2006-01-02")
+}
+
+// SaveIdea inserts idea or updates the existing row with the same slug.
+// scrapedAt dates the row when the idea carries no parsable date.
+func (s *SQLite) SaveIdea(ctx context.Context, idea *model.IdeaData, scrapedAt time.Time) error {
+	if idea.Slug == "" {
+		return fmt.Errorf("idea has no slug")
+	}
+
+	data, err := json.Marshal(idea)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	var valueScore, audienceScore, communityScore, productScore int
+	var marketPosition string
+	// Value equation components in model.ValueComponents order, NULL when
+	// the idea lacks one
+	components := make([]interface{}, len(model.ValueComponents))
+	if fw := idea.FrameworkFit; fw != nil {
+		valueScore = fw.ValueEquation.Score
+		audienceScore = fw.ACPFramework.Audience
+		communityScore = fw.ACPFramework.Community
+		productScore = fw.ACPFramework.Product
+		marketPosition = fw.MarketMatrix.Position
+		for i, name := range model.ValueComponents {
+			if c := fw.ValueComponent(name); c != nil {
+				components[i] = c.Score
+			}
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO ideas (
+    slug, title, description, scrape_date, tags,
+    value_equation_score, acp_audience_score, acp_community_score,
+    acp_product_score, market_position,
+    value_dream_outcome, value_perceived_likelihood, value_time_delay,
+    value_effort_sacrifice, data
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, json(?))
+ON CONFLICT(slug) DO UPDATE SET
+    title = excluded.title,
+    description = excluded.description,
+    scrape_date = excluded.scrape_date,
+    tags = excluded.tags,
+    value_equation_score = excluded.value_equation_score,
+    acp_audience_score = excluded.acp_audience_score,
+    acp_community_score = excluded.acp_community_score,
+    acp_product_score = excluded.acp_product_score,
+    market_position = excluded.market_position,
+    value_dream_outcome = excluded.value_dream_outcome,
+    value_perceived_likelihood = excluded.value_perceived_likelihood,
+    value_time_delay = excluded.value_time_delay,
+    value_effort_sacrifice = excluded.value_effort_sacrifice,
+    data = excluded.data`,
+		idea.Slug, idea.Title, idea.Description, ScrapeDate(idea, scrapedAt), strings.Join(idea.Tags, ","),
+		valueScore, audienceScore, communityScore, productScore, marketPosition,
+		components[0], components[1], components[2], components[3], string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %v", idea.Slug, err)
+	}
+
+	if err := saveLinks(ctx, tx, idea); err != nil {
+		return fmt.Errorf("failed to save links of %s: %v", idea.Slug, err)
+	}
+	if err := saveMetrics(ctx, tx, idea); err != nil {
+		return fmt.Errorf("failed to save metrics of %s: %v", idea.Slug, err)
+	}
+
+	return tx.Commit()
+}
+
+// saveLinks replaces the stored links of idea with idea.Links.
+func saveLinks(ctx context.Context, tx *sql.Tx, idea *model.IdeaData) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM links WHERE idea_slug = ?", idea.Slug); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO links (idea_slug, page, url, host, anchor_text) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(idea_slug, page, url) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, link := range idea.Links {
+		u, err := url.Parse(link.URL)
+		if err != nil {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, idea.Slug, link.Page, link.URL, u.Hostname(), link.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveMetrics replaces the stored metrics of idea with idea.Metrics.
+func saveMetrics(ctx context.Context, tx *sql.Tx, idea *model.IdeaData) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM metrics WHERE idea_slug = ?", idea.Slug); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+INSERT INTO metrics (idea_slug, page, label, text, value, max_value, unit, period)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, m := range idea.Metrics {
+		// Bounds, pages and periods are NULL when the figure has none
+		var page, maxValue, period interface{}
+		if m.Page != "" {
+			page = m.Page
+		}
+		if m.Max != 0 {
+			maxValue = m.Max
+		}
+		if m.Period != "" {
+			period = m.Period
+		}
+		if _, err := stmt.ExecContext(ctx, idea.Slug, page, m.Label, m.Text, m.Value, maxValue, m.Unit, period); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasIdea reports whether an idea with slug is stored.
+func (s *SQLite) HasIdea(ctx context.Context, slug string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ideas WHERE slug = ?", slug).Scan(&n)
+	return n > 0, err
+}
+
+// CountIdeas returns the number of stored ideas.
+func (s *SQLite) CountIdeas(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ideas").Scan(&n)
+	return n, err
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+func TestSQLiteSaveIdea(t *testing.T) {
+	if !haveCgo {
+		t.Skip("SQLite needs cgo")
+	}
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ideas.db")
+	db, err := OpenSQLite(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var version int
+	if err := db.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	names, _ := filepath.Glob("migrations/*.sql")
+	if version != len(names) {
+		t.Errorf("user_version = %d, want %d", version, len(names))
+	}
+
+	idea := &model.IdeaData{
+		SchemaVersion: model.SchemaVersion,
+		Slug:          "obriens-pub",
+		Title:         "O'Brien's",
+		Description:   `Robert'); DROP TABLE ideas;--`,
+		Date:          "Jan 17, 2025",
+		Tags:          []string{"Food", "Local"},
+		FrameworkFit:  &model.FrameworkData{},
+		Links:         []model.Link{{Text: "Source", URL: "https://example.com/o'brien", Page: "why-now"}},
+		Metrics:       []model.Metric{{Label: "Market Size", Text: "$2.3B", Value: 2.3e9, Unit: "USD", Page: "market-gap"}},
+	}
+	idea.FrameworkFit.ValueEquation.Score = 8
+	idea.FrameworkFit.ValueEquation.Components = []model.ValueComponent{{Name: model.TimeDelay, Score: 6, MaxScore: 10}}
+	if err := db.SaveIdea(ctx, idea, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving again updates the row in place and replaces its links and
+	// metrics
+	idea.Description = "A pub"
+	idea.Links = nil
+	if err := db.SaveIdea(ctx, idea, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := db.CountIdeas(ctx); err != nil || n != 1 {
+		t.Fatalf("CountIdeas = %d, %v, want 1", n, err)
+	}
+	var title, description, date, tags string
+	var score, timeDelay int
+	var dream *int
+	err = db.db.QueryRowContext(ctx, `
+SELECT title, description, date(scrape_date), tags, value_equation_score, value_time_delay, value_dream_outcome
+FROM ideas WHERE slug = ?`, idea.Slug).Scan(&title, &description, &date, &tags, &score, &timeDelay, &dream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "O'Brien's" || description != "A pub" || date != "2025-01-17" || tags != "Food,Local" {
+		t.Errorf("row = %q, %q, %q, %q", title, description, date, tags)
+	}
+	if score != 8 || timeDelay != 6 || dream != nil {
+		t.Errorf("scores = %d, %d, %v", score, timeDelay, dream)
+	}
+
+	var links, metrics int
+	db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM links WHERE idea_slug = ?", idea.Slug).Scan(&links)
+	db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM metrics WHERE idea_slug = ?", idea.Slug).Scan(&metrics)
+	if links != 0 || metrics != 1 {
+		t.Errorf("links = %d, metrics = %d, want 0 and 1", links, metrics)
+	}
+
+	// Reopening applies no migration twice
+	db.Close()
+	if db, err = OpenSQLite(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if ok, err := db.HasIdea(ctx, idea.Slug); err != nil || !ok {
+		t.Errorf("HasIdea after reopening = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/storage"
+	"github.com/rubinkazan/ideabrowser-scraper/validate"
+)
+
+// Store saves ideas as JSON files with their completeness reports, and to
+// a database. A Client saves through the Store built from its Config; a
+// Store can also be used on its own, to save ideas parsed without a
+// session.
+type Store struct {
+	// DB, when set, receives every saved idea.
+	DB *storage.SQLite
+
+	// Logger receives progress output; defaults to log.Default().
+	Logger *log.Logger
+
+	// MinCompleteness is the completeness, from 0 to 1, below which the
+	// report of a saved idea flags it; defaults to
+	// validate.DefaultMinCompleteness.
+	MinCompleteness float64
+
+	// Strict makes Save return an *IncompleteError, after saving, for
+	// ideas that do not match the schema or fall below MinCompleteness.
+	Strict bool
+}
+
+// IncompleteError is returned by a strict Store for an idea that was saved
+// but does not match the schema or is less complete than required.
+type IncompleteError struct {
+	Report *validate.Report
+	Min    float64
+}
+
+func (e *IncompleteError) Error() string {
+	if n := len(e.Report.Errors); n > 0 {
+		return fmt.Sprintf("%s: %d schema errors", e.Report.Slug, n)
+	}
+	return fmt.Sprintf("%s: %.0f%% complete, below the required %.0f%%",
+		e.Report.Slug, e.Report.Completeness*100, e.Min*100)
+}
+
+// Save saves idea as JSON in outputDir, filed under day, and to the
+// database when the store has one. It then checks the idea against the
+// schema, logs the completeness report and writes it next to the JSON file.
+func (s *Store) Save(ctx context.Context, idea *model.IdeaData, outputDir string, day time.Time) error {
+	logger := s.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	min := s.MinCompleteness
+	if min <= 0 {
+		min = validate.DefaultMinCompleteness
+	}
+
+	filePath, err := storage.WriteJSON(outputDir, idea, day)
+	if err != nil {
+		return err
+	}
+	logger.Printf("✓ Saved idea data to: %s", filePath)
+
+	if s.DB != nil {
+		if err := s.DB.SaveIdea(ctx, idea, day); err != nil {
+			return err
+		}
+		logger.Printf("✓ Stored idea in database: %s", idea.Slug)
+	}
+
+	report, err := validate.Check(idea)
+	if err != nil {
+		return fmt.Errorf("failed to validate %s: %v", idea.Slug, err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(report.String(), "\n"), "\n") {
+		logger.Print(line)
+	}
+	if _, err := storage.WriteReport(filePath, report.String()); err != nil {
+		return err
+	}
+	if !report.OK(min) {
+		if s.Strict {
+			return &IncompleteError{Report: report, Min: min}
+		}
+		logger.Printf("Warning: %s is incomplete or does not match the schema", idea.Slug)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+// Package validate checks scraped ideas against the declared IdeaData JSON
+// Schema and reports how completely they were extracted.
+package validate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+)
+
+// SchemaJSON is the JSON Schema of the IdeaData layout. Properties marked
+// "x-section" start a section of the completeness report, and properties
+// marked "x-optional" are not counted in it.
+//
+//go:embed schema.json
+var SchemaJSON string
+
+// DefaultMinCompleteness is the completeness an idea is expected to reach
+// when no other threshold is given.
+const DefaultMinCompleteness = 0.7
+
+// Overview is the section of the top-level fields outside any x-section.
+const Overview = "overview"
+
+// maxListed is the most missing fields a report line names; sections
+// missing more only give counts.
+const maxListed = 3
+
+var schema = jsonschema.MustCompileString("schema.json", SchemaJSON)
+
+// field is a property counted in the completeness report.
+type field struct {
+	section string
+	// name is the path of the field within its section, e.g.
+	// "audience.demographics"
+	name string
+	// path is the path of the field in the idea's JSON
+	path []string
+}
+
+// fields are the counted fields by section, in schema order of the
+// sections and name order within them.
+var fields = schemaFields()
+
+func schemaFields() []field {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(SchemaJSON), &doc); err != nil {
+		panic(fmt.Sprintf("validate: bad schema: %v", err))
+	}
+	var out []field
+	collectFields(doc, doc, Overview, nil, nil, &out)
+
+	// Sections are declared once each, so their marker's offset gives the
+	// schema order the decoded maps lose
+	offset := func(section string) int {
+		return strings.Index(SchemaJSON, `"x-section": "`+section+`"`)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].section != out[j].section {
+			return offset(out[i].section) < offset(out[j].section)
+		}
+		return out[i].name < out[j].name
+	})
+	return out
+}
+
+// collectFields adds the counted fields of the schema node s at path to out.
+// Objects with properties are descended into and any other node is one
+// field.
+func collectFields(root, s map[string]interface{}, section string, path, rel []string, out *[]field) {
+	s = resolve(root, s)
+	if optional, _ := s["x-optional"].(bool); optional {
+		return
+	}
+	if name, ok := s["x-section"].(string); ok {
+		section, rel = name, nil
+	}
+	props, _ := s["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		name := strings.Join(rel, ".")
+		if name == "" && len(path) > 0 {
+			name = path[len(path)-1]
+		}
+		*out = append(*out, field{section: section, name: name, path: path})
+		return
+	}
+	for name, child := range props {
+		child, _ := child.(map[string]interface{})
+		collectFields(root, child, section, extend(path, name), extend(rel, name), out)
+	}
+}
+
+func extend(path []string, name string) []string {
+	return append(append([]string(nil), path...), name)
+}
+
+// resolve follows a local "#/$defs/name" reference.
+func resolve(root, s map[string]interface{}) map[string]interface{} {
+	ref, ok := s["$ref"].(string)
+	if !ok {
+		return s
+	}
+	defs, _ := root["$defs"].(map[string]interface{})
+	if target, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}); ok {
+		return target
+	}
+	return s
+}
+
+// Report is the result of checking an idea.
+type Report struct {
+	Slug string `json:"slug"`
+	// Completeness is the mean of the sections' filled share, from 0 to 1,
+	// so a large section such as acp weighs no more than a small one.
+	Completeness float64   `json:"completeness"`
+	Sections     []Section `json:"sections"`
+	// Walls are the pages that showed a wall instead of their content, with
+	// their model.Access class, which explain the sections they left empty.
+	Walls map[string]string `json:"walls,omitempty"`
+	// Errors are the places the idea does not match the schema.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Section is the completeness of one section of an idea.
+type Section struct {
+	Name    string   `json:"name"`
+	Filled  int      `json:"filled"`
+	Total   int      `json:"total"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Check validates idea against the schema and counts the fields its
+// extractors filled. A field is filled when it is present and not a zero
+// value, so an unparsed score of 0 counts as missing.
+func Check(idea *model.IdeaData) (*Report, error) {
+	data, err := json.Marshal(idea)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %v", err)
+	}
+
+	r := &Report{Slug: idea.Slug}
+	for key, class := range idea.PageAccess {
+		if class == model.AccessOK {
+			continue
+		}
+		if r.Walls == nil {
+			r.Walls = make(map[string]string)
+		}
+		r.Walls[key] = class
+	}
+	if err := schema.Validate(doc); err != nil {
+		ve, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return nil, err
+		}
+		r.Errors = leafErrors(ve, nil)
+	}
+
+	for _, f := range fields {
+		if n := len(r.Sections); n == 0 || r.Sections[n-1].Name != f.section {
+			r.Sections = append(r.Sections, Section{Name: f.section})
+		}
+		s := &r.Sections[len(r.Sections)-1]
+		s.Total++
+		if filled(lookup(doc, f.path)) {
+			s.Filled++
+		} else {
+			s.Missing = append(s.Missing, f.name)
+		}
+	}
+	for _, s := range r.Sections {
+		r.Completeness += float64(s.Filled) / float64(s.Total)
+	}
+	if len(r.Sections) > 0 {
+		r.Completeness /= float64(len(r.Sections))
+	}
+	return r, nil
+}
+
+// leafErrors flattens a validation error into its innermost causes, which
+// name the offending values.
+func leafErrors(ve *jsonschema.ValidationError, out []string) []string {
+	if len(ve.Causes) == 0 {
+		location := ve.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return append(out, location+": "+ve.Message)
+	}
+	for _, cause := range ve.Causes {
+		out = leafErrors(cause, out)
+	}
+	return out
+}
+
+func lookup(v interface{}, path []string) interface{} {
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func filled(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	case float64:
+		return v != 0
+	case bool:
+		return v
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// OK reports whether the idea matches the schema and is at least min
+// complete.
+func (r *Report) OK(min float64) bool {
+	return len(r.Errors) == 0 && r.Completeness >= min
+}
+
+// String formats the report with one line per section, such as
+// "acp: 14/20 fields" or "value_equation: 2/3 fields, score missing",
+// followed by the pages behind a wall, such as "page acp: auth_required",
+// and the schema errors.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %.0f%% complete", r.Slug, r.Completeness*100)
+	if len(r.Errors) > 0 {
+		fmt.Fprintf(&b, ", %d schema errors", len(r.Errors))
+	}
+	b.WriteByte('\n')
+	for _, s := range r.Sections {
+		fmt.Fprintf(&b, "  %s: %d/%d fields", s.Name, s.Filled, s.Total)
+		if n := len(s.Missing); n > 0 && n <= maxListed && s.Filled > 0 {
+			fmt.Fprintf(&b, ", %s missing", strings.Join(s.Missing, ", "))
+		}
+		b.WriteByte('\n')
+	}
+	keys := make([]string, 0, len(r.Walls))
+	for key := range r.Walls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  page %s: %s\n", key, r.Walls[key])
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(&b, "  schema: %s\n", e)
+	}
+	return b.String()
+}
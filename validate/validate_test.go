@@ -0,0 +1,139 @@
+package validate_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rubinkazan/ideabrowser-scraper/model"
+	"github.com/rubinkazan/ideabrowser-scraper/validate"
+)
+
+func loadIdea(t *testing.T, path string) *model.IdeaData {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idea, _, err := model.Upgrade(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idea
+}
+
+// The extractor goldens are what the scraper writes, so they must match the
+// schema.
+func TestGoldensMatchSchema(t *testing.T) {
+	files, err := filepath.Glob("../extract/testdata/golden/*_parse.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no goldens found: %v", err)
+	}
+	for _, file := range files {
+		r, err := validate.Check(loadIdea(t, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Errors) > 0 {
+			t.Errorf("%s: schema errors:\n%s", filepath.Base(file), strings.Join(r.Errors, "\n"))
+		}
+	}
+}
+
+func TestCheckComplete(t *testing.T) {
+	r, err := validate.Check(loadIdea(t, "../extract/testdata/golden/nook_parse.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Completeness != 1 || !r.OK(validate.DefaultMinCompleteness) {
+		t.Errorf("nook: got\n%s", r)
+	}
+}
+
+func TestCheckMissingFields(t *testing.T) {
+	idea := &model.IdeaData{
+		SchemaVersion: model.SchemaVersion,
+		Slug:          "half-done",
+		Title:         "Half Done",
+		Description:   "An idea whose extractors mostly failed",
+		Date:          "Jan 17, 2025",
+		Tags:          []string{"Test"},
+		FrameworkFit:  &model.FrameworkData{},
+		ACP:           &model.ACPData{},
+	}
+	idea.FrameworkFit.ValueEquation.Rating = "Good"
+	idea.FrameworkFit.ValueEquation.Components = []model.ValueComponent{{Name: model.DreamOutcome, Score: 8, MaxScore: 10}}
+	idea.ACP.Audience.Demographics = "Remote workers"
+	idea.PageAccess = map[string]string{"/idea-of-the-day": model.AccessOK, "build/landing-page": model.AccessPlanRestricted}
+
+	r, err := validate.Check(idea)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Errors) > 0 {
+		t.Errorf("unexpected schema errors: %v", r.Errors)
+	}
+
+	lines := strings.Split(r.String(), "\n")
+	for _, want := range []string{
+		"  overview: 4/4 fields",
+		"  value_equation: 2/3 fields, score missing",
+		"  acp: 1/28 fields",
+		"  build_info: 0/5 fields",
+		"  page build/landing-page: plan_restricted",
+	} {
+		found := false
+		for _, line := range lines {
+			found = found || line == want
+		}
+		if !found {
+			t.Errorf("report lacks %q:\n%s", want, r)
+		}
+	}
+	if r.OK(validate.DefaultMinCompleteness) {
+		t.Errorf("completeness %.2f passed the default threshold", r.Completeness)
+	}
+}
+
+// TestSlugPattern checks the schema and the extractors agree on what a slug
+// is.
+func TestSlugPattern(t *testing.T) {
+	var schema struct {
+		Properties struct {
+			Slug struct {
+				Pattern string `json:"pattern"`
+			} `json:"slug"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(validate.SchemaJSON), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := schema.Properties.Slug.Pattern, "^"+model.SlugPattern+"$"; got != want {
+		t.Errorf("schema slug pattern = %q, want %q", got, want)
+	}
+}
+
+func TestCheckSchemaErrors(t *testing.T) {
+	idea := &model.IdeaData{SchemaVersion: model.SchemaVersion, Slug: "Not A Slug", FrameworkFit: &model.FrameworkData{}}
+	idea.FrameworkFit.ValueEquation.Score = 12
+	idea.Links = []model.Link{{URL: "/relative"}}
+
+	r, err := validate.Check(idea)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, location := range []string{"/slug", "/framework_fit/value_equation/score", "/links/0/url"} {
+		found := false
+		for _, e := range r.Errors {
+			found = found || strings.HasPrefix(e, location+": ")
+		}
+		if !found {
+			t.Errorf("no schema error at %s in %v", location, r.Errors)
+		}
+	}
+	if r.OK(0) {
+		t.Error("report with schema errors is OK")
+	}
+}